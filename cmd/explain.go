@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/j0356/eol-scanner/core/scanning"
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [image] [package]",
+	Short: "Explain how a package was matched to an EOL product",
+	Long: `Explain how a package in a container image is matched against the
+EOL database.
+
+Every lookup of the matching chain is shown in order (user mapping,
+exact PURL, distro PURL, PURL prefix, CPE, product name, alias and
+repology), with the identifier that matched, the confidence of the
+match and why each lookup failed or was not used. Use it to debug false positives and
+unknown components. Lookups are labeled with the matcher that made them when it
+differs from the match method; pass that name to --disable-matcher to leave the
+matcher out. The result is the one a scan reports, after distro support,
+mixed-release checks and merging binaries into their source package.
+
+Examples:
+  # Explain how express is matched in a Docker image
+  eol-scanner explain node:18 express
+
+  # Explain a package by PURL, as JSON
+  eol-scanner explain --output json python:3.9 pkg:pypi/django@4.2.1
+
+  # Explain a package in a tar archive
+  eol-scanner explain --source tar ./image.tar openssl`,
+	Args: cobra.ExactArgs(2),
+	RunE: runExplain,
+}
+
+func init() {
+	explainCmd.Flags().StringVarP(&sourceType, "source", "s", "docker", "Image source type: docker, registry, tar")
+	explainCmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format: table, json")
+	explainCmd.Flags().IntVarP(&forwardLookupDays, "days", "d", 90, "Forward lookup days for upcoming EOL")
+	explainCmd.Flags().BoolVar(&noUpdateDB, "no-update", false, "Skip automatic database update")
+	explainCmd.Flags().BoolVar(&extendedSupport, "extended-support", false, "Treat cycles as supported until their extended support ends (ESM, ELS, etc.)")
+	explainCmd.Flags().BoolVar(&distroSupport, "distro-support", false, "Evaluate distro packages (deb, rpm, apk) against the support of their distro release instead of upstream")
+	explainCmd.Flags().StringVar(&mappingFile, "mappings", "", "Package-to-product mapping file (YAML or JSON), consulted before built-in matching")
+	explainCmd.Flags().StringSliceVar(&disabledMatchers, "disable-matcher", nil, "Leave a matcher out of the matching chain (e.g. cpe, name); repeatable")
+	explainCmd.Flags().StringVar(&registryUser, "registry-user", "", "Registry username for authentication")
+	explainCmd.Flags().StringVar(&registryPass, "registry-pass", "", "Registry password for authentication")
+	explainCmd.Flags().StringVar(&registryToken, "registry-token", "", "Registry token for token-based authentication")
+	explainCmd.Flags().StringVar(&registryCert, "registry-cert", "", "Client certificate path for mTLS authentication")
+	explainCmd.Flags().StringVar(&registryKey, "registry-key", "", "Client key path for mTLS authentication")
+	explainCmd.Flags().StringVar(&registryCA, "registry-ca", "", "Custom CA certificate file or directory")
+
+	rootCmd.AddCommand(explainCmd)
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	imageRef := args[0]
+	packageName := args[1]
+	ctx := context.Background()
+
+	quiet := strings.EqualFold(outputFormat, "json")
+	if !quiet {
+		fmt.Printf("📋 Initializing EOL scanner...\n")
+	}
+
+	scanner, err := scanning.NewScanner(buildScannerConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create scanner: %w", err)
+	}
+	defer scanner.Close()
+
+	if !quiet {
+		fmt.Printf("🔍 Generating SBOM for %s...\n", imageRef)
+	}
+
+	var explanations []scanning.ComponentExplanation
+	switch strings.ToLower(sourceType) {
+	case "docker":
+		explanations, err = scanner.ExplainFromDocker(ctx, imageRef, packageName)
+	case "registry":
+		explanations, err = scanner.ExplainFromRegistry(ctx, imageRef, packageName)
+	case "tar":
+		explanations, err = scanner.ExplainFromTar(ctx, imageRef, packageName)
+	default:
+		return fmt.Errorf("unknown source type: %s (use: docker, registry, tar)", sourceType)
+	}
+
+	if err != nil {
+		return fmt.Errorf("explain failed: %w", err)
+	}
+
+	switch strings.ToLower(outputFormat) {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(explanations)
+	case "table":
+		return outputExplanations(packageName, explanations)
+	default:
+		return fmt.Errorf("unknown output format: %s (use: table, json)", outputFormat)
+	}
+}
+
+func outputExplanations(packageName string, explanations []scanning.ComponentExplanation) error {
+	if len(explanations) == 0 {
+		fmt.Printf("\n❓ No package named %q found in the image.\n", packageName)
+		return nil
+	}
+
+	for _, e := range explanations {
+		fmt.Printf("\n📦 %s %s (%s)\n", e.Name, e.Version, e.Type)
+		if e.PURL != "" {
+			fmt.Printf("   PURL: %s\n", e.PURL)
+		}
+		for _, cpe := range e.CPEs {
+			fmt.Printf("   CPE:  %s\n", cpe)
+		}
+		for _, location := range e.Result.Locations {
+			fmt.Printf("   Path: %s\n", location)
+		}
+		if e.Result.LayerDigest != "" {
+			fmt.Printf("   Layer: %s", e.Result.LayerDigest)
+			if e.Result.LayerSource != "" {
+				fmt.Printf(" (%s)", e.Result.LayerSource)
+			}
+			fmt.Println()
+			if e.Result.LayerInstruction != "" {
+				fmt.Printf("          %s\n", truncate(e.Result.LayerInstruction, 100))
+			}
+		}
+		fmt.Println(strings.Repeat("─", 85))
+
+		for i, a := range e.Attempts {
+			icon := "✗"
+			switch {
+			case a.Selected:
+				icon = "✅"
+			case a.Matched:
+				icon = "➖"
+			case strings.HasPrefix(a.Reason, "skipped"):
+				icon = "·"
+			}
+
+			fmt.Printf("%2d. %s %-12s %s", i+1, icon, a.Method, a.Query)
+			if a.Matcher != string(a.Method) {
+				fmt.Printf(" [%s]", a.Matcher)
+			}
+			fmt.Println()
+			if a.Matched {
+				fmt.Printf("       → %s via %s\n", a.Product, a.Identifier)
+			}
+			for _, alternative := range a.Alternatives {
+				fmt.Printf("       also %s via %s\n", alternative.Product, alternative.Identifier)
+			}
+			fmt.Printf("       %s\n", a.Reason)
+		}
+
+		fmt.Println(strings.Repeat("─", 85))
+		r := e.Result
+		if r.Name != e.Name {
+			fmt.Printf("Reported as %s, with %s\n", r.Name, strings.Join(r.Binaries, ", "))
+		}
+		if r.MatchedProduct == "" {
+			fmt.Printf("Result: no product matched, status %s\n", r.Status)
+			continue
+		}
+		fmt.Printf("Result: %s cycle %s, status %s", r.MatchedProduct, r.MatchedCycle, r.Status)
+		if r.Match != nil {
+			fmt.Printf(" (%s, %s confidence)", r.Match.Method, r.Match.Confidence)
+		}
+		fmt.Println()
+		if r.Match != nil && r.Match.Ambiguous {
+			fmt.Printf("        %s matched equally well; ties are broken by product name\n", r.Match.Alternatives[0].Product)
+		}
+		if len(r.AmbiguousCycles) > 0 {
+			fmt.Printf("        also matched cycles %s equally well\n", strings.Join(r.AmbiguousCycles, ", "))
+		}
+		if r.CycleFallback {
+			fmt.Printf("        no cycle matched version %s; took the closest cycle of its major\n", r.Version)
+		}
+		if r.OriginRelease != nil {
+			fmt.Printf("        built for %s %s\n", r.OriginRelease.Distro, r.OriginRelease.Release)
+		}
+		if r.Upstream != nil && r.DistroSupport != nil {
+			fmt.Printf("        status follows %s %s support; upstream status %s\n",
+				r.DistroSupport.Product, r.DistroSupport.Cycle, r.Upstream.Status)
+		}
+	}
+
+	return nil
+}
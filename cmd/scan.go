@@ -20,6 +20,12 @@ var (
 	outputFormat      string
 	noUpdateDB        bool
 	onlyEOL           bool
+	groupFindings     bool
+	extendedSupport   bool
+	distroSupport     bool
+	mappingFile       string
+	disabledMatchers  []string
+	suggestMappings   string
 	registryUser      string
 	registryPass      string
 	registryToken     string
@@ -57,7 +63,25 @@ Examples:
   eol-scanner scan --output json alpine:latest
 
   # Show only EOL components
-  eol-scanner scan --only-eol ubuntu:20.04`,
+  eol-scanner scan --only-eol ubuntu:20.04
+
+  # Group packages of the same product and cycle into one finding
+  eol-scanner scan --group debian:bullseye
+
+  # Treat cycles covered by paid extended support (ESM, ELS) as supported
+  eol-scanner scan --extended-support ubuntu:18.04
+
+  # Evaluate distro packages against the support of their distro release
+  eol-scanner scan --distro-support redhat/ubi8:latest
+
+  # Map in-house packages to EOL products with a mapping file
+  eol-scanner scan --mappings ./eol-mappings.yaml myorg/app:latest
+
+  # Skip the noisy CPE and package name matchers
+  eol-scanner scan --disable-matcher cpe --disable-matcher name myorg/app:latest
+
+  # Write mappings for unknown components to the products they resemble
+  eol-scanner scan --suggest-mappings ./suggested-mappings.yaml myorg/app:latest`,
 	Args: cobra.ExactArgs(1),
 	RunE: runScan,
 }
@@ -68,6 +92,12 @@ func init() {
 	scanCmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format: table, json")
 	scanCmd.Flags().BoolVar(&noUpdateDB, "no-update", false, "Skip automatic database update")
 	scanCmd.Flags().BoolVar(&onlyEOL, "only-eol", false, "Only show EOL and EOL-soon components")
+	scanCmd.Flags().BoolVar(&groupFindings, "group", false, "Show one row per product and cycle instead of one per package")
+	scanCmd.Flags().BoolVar(&extendedSupport, "extended-support", false, "Treat cycles as supported until their extended support ends (ESM, ELS, etc.)")
+	scanCmd.Flags().BoolVar(&distroSupport, "distro-support", false, "Evaluate distro packages (deb, rpm, apk) against the support of their distro release instead of upstream")
+	scanCmd.Flags().StringVar(&mappingFile, "mappings", "", "Package-to-product mapping file (YAML or JSON), consulted before built-in matching")
+	scanCmd.Flags().StringSliceVar(&disabledMatchers, "disable-matcher", nil, "Leave a matcher out of the matching chain (e.g. cpe, name); repeatable")
+	scanCmd.Flags().StringVar(&suggestMappings, "suggest-mappings", "", "Write a mapping file suggesting products for unknown components")
 	scanCmd.Flags().StringVar(&registryUser, "registry-user", "", "Registry username for authentication")
 	scanCmd.Flags().StringVar(&registryPass, "registry-pass", "", "Registry password for authentication")
 	scanCmd.Flags().StringVar(&registryToken, "registry-token", "", "Registry token for token-based authentication")
@@ -90,31 +120,7 @@ func runScan(cmd *cobra.Command, args []string) error {
 	}
 
 	// Build scanner config
-	config := &scanning.ScannerConfig{
-		DBPath:            dbPath,
-		ForwardLookupDays: forwardLookupDays,
-		AutoUpdateDB:      !noUpdateDB,
-		DBMaxAge:          7 * 24 * time.Hour,
-	}
-
-	// Build registry credentials if any auth flags are provided
-	if registryUser != "" || registryToken != "" || registryCert != "" || registryCA != "" {
-		config.RegistryAuth = &sbomgen.RegistryCredentials{
-			Username:   registryUser,
-			Password:   registryPass,
-			Token:      registryToken,
-			ClientCert: registryCert,
-			ClientKey:  registryKey,
-		}
-		config.RegistryCAFileOrDir = registryCA
-	}
-
-	// Add progress callback if verbose
-	if verbose {
-		config.ProgressCallback = func(stage, message string) {
-			fmt.Printf("[%s] %s\n", stage, message)
-		}
-	}
+	config := buildScannerConfig()
 
 	// Create scanner
 	scanner, err := scanning.NewScanner(config)
@@ -159,6 +165,17 @@ func runScan(cmd *cobra.Command, args []string) error {
 		fmt.Printf("✅ Analysis complete. Found %d components.\n", summary.TotalComponents)
 	}
 
+	// Notes about the mapping file go to stderr, so they never mix with JSON output
+	if suggestMappings != "" && summary.SuggestionsUnavailable {
+		fmt.Fprintf(os.Stderr, "⚠️ Product suggestions are unavailable because the EOL index failed to load; %s was not written\n", suggestMappings)
+	} else if suggestMappings != "" {
+		written, err := writeSuggestedMappings(suggestMappings, summary)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "📝 Wrote %d suggested mapping(s) to %s\n", written, suggestMappings)
+	}
+
 	// Output results
 	switch strings.ToLower(outputFormat) {
 	case "json":
@@ -170,15 +187,68 @@ func runScan(cmd *cobra.Command, args []string) error {
 	}
 }
 
+func buildScannerConfig() *scanning.ScannerConfig {
+	config := &scanning.ScannerConfig{
+		DBPath:            dbPath,
+		ForwardLookupDays: forwardLookupDays,
+		AutoUpdateDB:      !noUpdateDB,
+		DBMaxAge:          7 * 24 * time.Hour,
+		ExtendedSupport:   extendedSupport,
+		DistroSupport:     distroSupport,
+		MappingFile:       mappingFile,
+		DisabledMatchers:  disabledMatchers,
+		SuggestProducts:   suggestMappings != "",
+	}
+
+	// Build registry credentials if any auth flags are provided
+	if registryUser != "" || registryToken != "" || registryCert != "" || registryCA != "" {
+		config.RegistryAuth = &sbomgen.RegistryCredentials{
+			Username:   registryUser,
+			Password:   registryPass,
+			Token:      registryToken,
+			ClientCert: registryCert,
+			ClientKey:  registryKey,
+		}
+		config.RegistryCAFileOrDir = registryCA
+	}
+
+	// Add progress callback if verbose
+	if verbose {
+		config.ProgressCallback = func(stage, message string) {
+			fmt.Printf("[%s] %s\n", stage, message)
+		}
+	}
+
+	return config
+}
+
+// writeSuggestedMappings writes the products suggested for unknown components as a
+// mapping file to review and pass to --mappings, returning the number of mappings
+func writeSuggestedMappings(path string, summary *scanning.ScanSummary) (int, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create suggested mappings: %w", err)
+	}
+	defer file.Close()
+
+	written, err := scanning.WriteSuggestedMappings(file, summary.Components)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write suggested mappings: %w", err)
+	}
+	return written, nil
+}
+
 func outputJSON(summary *scanning.ScanSummary) error {
 	var output interface{}
 	if onlyEOL {
 		output = struct {
 			*scanning.ScanSummary
 			Components []scanning.ComponentResult `json:"components"`
+			Findings   []scanning.Finding         `json:"findings"`
 		}{
 			ScanSummary: summary,
 			Components:  summary.GetEOLComponents(),
+			Findings:    summary.GetEOLFindings(),
 		}
 	} else {
 		output = summary
@@ -194,15 +264,38 @@ func outputTable(summary *scanning.ScanSummary) error {
 	fmt.Printf("\n🔍 EOL Scan Results for: %s\n", summary.ImageReference)
 	fmt.Printf("   Scan Time: %s\n", summary.ScanTime.Format("2006-01-02 15:04:05"))
 	fmt.Printf("   Forward Lookup: %d days\n", summary.ForwardLookupDays)
+	if summary.ExtendedSupport {
+		fmt.Printf("   Extended Support: enabled\n")
+	}
+	if summary.DistroSupport {
+		fmt.Printf("   Distro Support: enabled\n")
+	}
 	fmt.Println(strings.Repeat("─", 85))
 
 	// Print summary
 	fmt.Printf("\n📊 Summary:\n")
-	fmt.Printf("   Total Components: %d\n", summary.TotalComponents)
-	fmt.Printf("   ❌ EOL:            %d\n", summary.EOLComponents)
-	fmt.Printf("   ⚠️ EOL Soon:       %d\n", summary.EOLSoonComponents)
-	fmt.Printf("   ✅ Active:         %d\n", summary.ActiveComponents)
+	fmt.Printf("   Total Components: %d (%d distinct product cycles)\n", summary.TotalComponents, summary.TotalFindings)
+	fmt.Printf("   ❌ EOL:            %d (%d distinct)\n", summary.EOLComponents, summary.EOLFindings)
+	fmt.Printf("   ⚠️ EOL Soon:       %d (%d distinct)\n", summary.EOLSoonComponents, summary.EOLSoonFindings)
+	fmt.Printf("   🔒 Security Only:  %d (%d distinct)\n", summary.SecurityOnlyComponents, summary.SecurityOnlyFindings)
+	fmt.Printf("   ✅ Active:         %d (%d distinct)\n", summary.ActiveComponents, summary.ActiveFindings)
+	if summary.RollingComponents > 0 {
+		fmt.Printf("   🔄 Rolling:        %d (%d distinct)\n", summary.RollingComponents, summary.RollingFindings)
+	}
 	fmt.Printf("   ❓ Unknown:        %d\n", summary.UnknownComponents)
+	if summary.OutdatedInCycleComponents > 0 {
+		fmt.Printf("   ⬆️ Outdated:       %d (behind latest release of their cycle)\n", summary.OutdatedInCycleComponents)
+	}
+	if summary.MixedReleaseComponents > 0 {
+		fmt.Printf("   🧬 Mixed Release:  %d (built for an older distro release)\n", summary.MixedReleaseComponents)
+	}
+	if summary.DistroSupportedComponents > 0 {
+		fmt.Printf("   🛡️ Distro Support: %d (upstream EOL, supported by their distro release)\n", summary.DistroSupportedComponents)
+	}
+
+	if groupFindings {
+		return outputFindingsTable(summary)
+	}
 
 	// Get components to display
 	var components []scanning.ComponentResult
@@ -221,12 +314,19 @@ func outputTable(summary *scanning.ScanSummary) error {
 
 	// Print component details
 	fmt.Printf("\n📦 Components:\n")
-	fmt.Println(strings.Repeat("─", 90))
-	fmt.Printf("%-32s %-18s %-8s  %-12s %s\n", "NAME", "VERSION", "STATUS", "EOL DATE", "DAYS")
-	fmt.Println(strings.Repeat("─", 90))
+	fmt.Println(strings.Repeat("─", 115))
+	fmt.Printf("%-32s %-18s %-8s  %-12s %-6s %-12s %s\n", "NAME", "VERSION", "STATUS", "EOL DATE", "DAYS", "SUPPORT END", "EXT SUPPORT")
+	fmt.Println(strings.Repeat("─", 115))
 
 	for _, c := range components {
-		name := truncate(c.Name, 32)
+		name := c.Name
+		if len(c.Binaries) > 1 {
+			name = fmt.Sprintf("%s (%d binaries)", c.Name, len(c.Binaries))
+		}
+		if c.Vendor != "" {
+			name = fmt.Sprintf("%s (%s)", name, c.Vendor)
+		}
+		name = truncate(name, 32)
 		version := truncate(c.Version, 18)
 		statusIcon, statusText := statusParts(c.Status)
 		eolDate := formatEOLDate(c.EOLDate)
@@ -234,41 +334,271 @@ func outputTable(summary *scanning.ScanSummary) error {
 		if c.DaysUntilEOL != nil {
 			daysLeft = fmt.Sprintf("%d", *c.DaysUntilEOL)
 		}
+		supportEnd := formatEOLDate(c.SupportEndDate)
+		extendedEnd := formatEOLDate(c.ExtendedSupportEndDate)
+		if extendedEnd == "-" && c.HasExtendedSupport {
+			extendedEnd = "yes"
+		}
 
-		fmt.Printf("%-32s %-18s %s %-6s %-12s %s\n", name, version, statusIcon, statusText, eolDate, daysLeft)
+		fmt.Printf("%-32s %-18s %s %-6s %-12s %-6s %-12s %s\n", name, version, statusIcon, statusText, eolDate, daysLeft, supportEnd, extendedEnd)
 	}
 
-	fmt.Println(strings.Repeat("─", 85))
+	fmt.Println(strings.Repeat("─", 115))
 
 	// Exit code hint
-	if summary.EOLComponents > 0 {
-		fmt.Printf("\n⚠️ Warning: %d component(s) have reached end-of-life!\n", summary.EOLComponents)
+	printEOLNotices(summary)
+	printComponentNotices(summary, components)
+	if summary.EOLComponents == 0 && summary.EOLSoonComponents == 0 {
+		fmt.Printf("\n✅ No end-of-life issues detected.\n")
 	}
-	if summary.EOLSoonComponents > 0 {
-		fmt.Printf("📅 Notice: %d component(s) will reach EOL within %d days.\n", summary.EOLSoonComponents, summary.ForwardLookupDays)
+
+	return nil
+}
+
+// printComponentNotices prints the notices about individual components that follow
+// both the per-package and the grouped table
+func printComponentNotices(summary *scanning.ScanSummary, components []scanning.ComponentResult) {
+	if summary.SecurityOnlyComponents > 0 {
+		fmt.Printf("🔒 Notice: %d component(s) are past active support and only receive security fixes.\n", summary.SecurityOnlyComponents)
 	}
-	if summary.EOLComponents == 0 && summary.EOLSoonComponents == 0 {
+	printRecommendations(components)
+	printMixedReleases(components)
+	printDistroSupported(components)
+	if summary.OutdatedInCycleComponents > 0 {
+		fmt.Printf("⬆️ Notice: %d component(s) are behind the latest release of their cycle:\n", summary.OutdatedInCycleComponents)
+		for _, c := range components {
+			if !c.OutdatedInCycle {
+				continue
+			}
+			fmt.Printf("   • %s %s → %s%s\n", c.Name, c.Version, c.LatestVersion, formatBehind(c))
+		}
+	}
+	for _, c := range components {
+		if c.Match != nil && c.Match.Ambiguous {
+			fmt.Printf("❔ Notice: %s %s matched product %s; %s matched equally well.\n",
+				c.Name, c.Version, c.MatchedProduct, c.Match.Alternatives[0].Product)
+		}
+		if len(c.AmbiguousCycles) > 0 {
+			fmt.Printf("❔ Notice: %s %s matched cycle %s; also matched %s equally well.\n",
+				c.Name, c.Version, c.MatchedCycle, strings.Join(c.AmbiguousCycles, ", "))
+		}
+		if c.CycleFallback {
+			fmt.Printf("❔ Notice: no cycle matches %s %s; assumed cycle %s, the closest of its major.\n",
+				c.Name, c.Version, c.MatchedCycle)
+		}
+	}
+	printSuggestions(summary.Components)
+}
+
+// outputFindingsTable prints one row per product and cycle, with the packages behind it
+func outputFindingsTable(summary *scanning.ScanSummary) error {
+	var findings []scanning.Finding
+	if onlyEOL {
+		findings = summary.GetEOLFindings()
+	} else {
+		findings = summary.Findings
+	}
+
+	if len(findings) == 0 {
+		if onlyEOL {
+			fmt.Println("\n✅ No EOL or EOL-soon findings.")
+		}
+		return nil
+	}
+
+	fmt.Printf("\n📦 Findings:\n")
+	fmt.Println(strings.Repeat("─", 115))
+	fmt.Printf("%-24s %-10s %-8s  %-12s %-6s %-12s %-8s %s\n", "PRODUCT", "CYCLE", "STATUS", "EOL DATE", "DAYS", "SUPPORT END", "PACKAGES", "COMPONENTS")
+	fmt.Println(strings.Repeat("─", 115))
+
+	for _, f := range findings {
+		statusIcon, statusText := statusParts(f.Status)
+		daysLeft := "-"
+		if f.DaysUntilEOL != nil {
+			daysLeft = fmt.Sprintf("%d", *f.DaysUntilEOL)
+		}
+		cycle := f.Cycle
+		if cycle == "" {
+			cycle = "-"
+		}
+		names := make([]string, 0, len(f.Components))
+		for _, c := range f.Components {
+			names = append(names, c.Name)
+		}
+
+		fmt.Printf("%-24s %-10s %s %-6s %-12s %-6s %-12s %-8d %s\n", truncate(f.Product, 24), truncate(cycle, 10),
+			statusIcon, statusText, formatEOLDate(f.EOLDate), daysLeft, formatEOLDate(f.SupportEndDate),
+			f.PackageCount, truncate(strings.Join(names, ", "), 40))
+	}
+
+	fmt.Println(strings.Repeat("─", 115))
+
+	components := summary.Components
+	if onlyEOL {
+		components = summary.GetEOLComponents()
+	}
+	printEOLNotices(summary)
+	printComponentNotices(summary, components)
+	if summary.EOLFindings == 0 && summary.EOLSoonFindings == 0 {
 		fmt.Printf("\n✅ No end-of-life issues detected.\n")
 	}
 
 	return nil
 }
 
+// printEOLNotices prints the EOL and EOL-soon counts per package and per product cycle
+func printEOLNotices(summary *scanning.ScanSummary) {
+	if summary.EOLComponents > 0 {
+		fmt.Printf("\n⚠️ Warning: %d component(s) in %d product cycle(s) have reached end-of-life!\n", summary.EOLComponents, summary.EOLFindings)
+	}
+	if summary.EOLSoonComponents > 0 {
+		fmt.Printf("📅 Notice: %d component(s) in %d product cycle(s) will reach EOL within %d days.\n",
+			summary.EOLSoonComponents, summary.EOLSoonFindings, summary.ForwardLookupDays)
+	}
+
+	// Tell platform teams which EOL components come with the base image they own
+	var base, application int
+	for _, c := range summary.GetEOLComponents() {
+		switch c.LayerSource {
+		case scanning.LayerSourceBase:
+			base++
+		case scanning.LayerSourceApplication:
+			application++
+		}
+	}
+	if base > 0 || application > 0 {
+		fmt.Printf("🧱 Origin: %d EOL/EOL-soon component(s) from the base image, %d from application layers.\n", base, application)
+	}
+}
+
+// printMixedReleases lists packages built for an older release of the image's distro
+func printMixedReleases(components []scanning.ComponentResult) {
+	header := false
+	for _, c := range components {
+		o := c.OriginRelease
+		if o == nil {
+			continue
+		}
+		if !header {
+			fmt.Printf("🧬 Notice: packages built for an older distro release get no updates from the OS release:\n")
+			header = true
+		}
+
+		details := string(o.Status)
+		if o.EOLDate != "" {
+			details += ", EOL " + formatEOLDate(o.EOLDate)
+		}
+		fmt.Printf("   • %s %s → %s %s (%s)\n", c.Name, c.Version, o.Distro, o.Release, details)
+	}
+}
+
+// printDistroSupported lists packages past their upstream EOL that their distro release
+// still supports
+func printDistroSupported(components []scanning.ComponentResult) {
+	header := false
+	for _, c := range components {
+		if !c.DistroSupported() {
+			continue
+		}
+		if !header {
+			fmt.Printf("🛡️ Notice: packages past upstream EOL that their distro release still supports:\n")
+			header = true
+		}
+
+		u, d := c.Upstream, c.DistroSupport
+		fmt.Printf("   • %s %s: upstream %s %s EOL %s, supported by %s %s until %s\n",
+			c.Name, c.Version, u.Product, u.Cycle, formatEOLDate(u.EOLDate), d.Product, d.Cycle, formatEOLDate(d.EOLDate))
+	}
+}
+
+// printSuggestions counts the unknown components that resemble a product, pointing to
+// --suggest-mappings when no mapping file was written
+func printSuggestions(components []scanning.ComponentResult) {
+	count := 0
+	for _, c := range components {
+		if len(c.Suggestions) > 0 {
+			count++
+		}
+	}
+	if count == 0 {
+		return
+	}
+	if suggestMappings == "" {
+		fmt.Printf("💡 Notice: %d unknown component(s) resemble EOL products; review them with --suggest-mappings.\n", count)
+	} else {
+		fmt.Printf("💡 Notice: %d unknown component(s) resemble EOL products; see %s.\n", count, suggestMappings)
+	}
+}
+
 func statusParts(status scanning.EOLStatus) (string, string) {
 	switch status {
 	case scanning.StatusEOL:
 		return "❌", "EOL"
 	case scanning.StatusEOLSoon:
 		return "⚠️", "SOON"
+	case scanning.StatusSecurityOnly:
+		return "🔒", "SEC"
 	case scanning.StatusActive:
 		return "✅", "OK"
 	case scanning.StatusUnknown:
 		return "❓", "N/A"
+	case scanning.StatusRolling:
+		return "🔄", "ROLL"
 	default:
 		return " ", string(status)
 	}
 }
 
+func printRecommendations(components []scanning.ComponentResult) {
+	header := false
+	for _, c := range components {
+		if len(c.Recommendations) == 0 {
+			continue
+		}
+		if !header {
+			fmt.Printf("💡 Recommended upgrades:\n")
+			header = true
+		}
+
+		var targets []string
+		for _, r := range c.Recommendations {
+			target := r.Cycle
+			if r.Version != "" {
+				target = r.Version
+			}
+			var details []string
+			switch r.Strategy {
+			case scanning.StrategyNewestLTS:
+				details = append(details, "newest LTS")
+			case scanning.StrategyNearestMaintained:
+				details = append(details, "nearest maintained cycle")
+			}
+			if r.EOLDate != "" {
+				details = append(details, "EOL "+formatEOLDate(r.EOLDate))
+			}
+			if r.DaysUntilEOL != nil {
+				details = append(details, fmt.Sprintf("%d days of support", *r.DaysUntilEOL))
+			}
+			targets = append(targets, fmt.Sprintf("%s (%s)", target, strings.Join(details, ", ")))
+		}
+		fmt.Printf("   • %s %s → %s\n", c.Name, c.Version, strings.Join(targets, ", then "))
+	}
+}
+
+func formatBehind(c scanning.ComponentResult) string {
+	var parts []string
+	if c.ReleasesBehind != nil {
+		parts = append(parts, fmt.Sprintf("%d release(s) behind", *c.ReleasesBehind))
+	}
+	if c.DaysSinceLatestRelease != nil {
+		parts = append(parts, fmt.Sprintf("latest released %d days ago", *c.DaysSinceLatestRelease))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
 func formatEOLDate(date string) string {
 	if date == "" {
 		return "-"
@@ -0,0 +1,324 @@
+package db
+
+import (
+	"sort"
+	"strings"
+)
+
+// lookupMethodRank orders lookup match methods: exact identifiers and product names
+// beat prefixes and aliases, which beat repology project names
+var lookupMethodRank = map[string]int{
+	MatchMethodPURL:       0,
+	MatchMethodCPE:        0,
+	MatchMethodName:       0,
+	MatchMethodPURLPrefix: 1,
+	MatchMethodCPEPrefix:  1,
+	MatchMethodAlias:      1,
+	MatchMethodRepology:   2,
+}
+
+// languagePackageTypes are the syft package types and PURL types of language
+// ecosystems, whose packages are frameworks and libraries or language runtimes
+var languagePackageTypes = map[string]bool{
+	"python":       true,
+	"pypi":         true,
+	"npm":          true,
+	"gem":          true,
+	"java-archive": true,
+	"maven":        true,
+	"go-module":    true,
+	"golang":       true,
+	"dotnet":       true,
+	"nuget":        true,
+	"php-composer": true,
+	"composer":     true,
+	"rust-crate":   true,
+	"cargo":        true,
+	"hex":          true,
+}
+
+// systemPackageTypes are the package types of distro packages and binaries, which are
+// runtimes, databases, servers and operating system components rather than frameworks
+var systemPackageTypes = map[string]bool{
+	"deb":    true,
+	"rpm":    true,
+	"apk":    true,
+	"alpm":   true,
+	"binary": true,
+}
+
+// candidateRank is the position of a candidate product; lower ranks are better
+type candidateRank struct {
+	method   int
+	category int
+}
+
+// rankOf ranks a candidate by how it matched and how well its category fits the type
+// of the package being looked up
+func rankOf(product Product, pkgType string) candidateRank {
+	rank := candidateRank{method: len(lookupMethodRank)}
+	if product.Match != nil {
+		if r, ok := lookupMethodRank[product.Match.Method]; ok {
+			rank.method = r
+		}
+	}
+	if !categoryFits(product.CategoryName.String, pkgType) {
+		rank.category = 1
+	}
+	return rank
+}
+
+// categoryFits reports whether a product category suits a package type: frameworks
+// and languages for language packages, anything but frameworks for distro packages and
+// binaries, and any category for an unknown type
+func categoryFits(category, pkgType string) bool {
+	switch {
+	case pkgType == "" || category == "" || strings.EqualFold(category, pkgType):
+		return true
+	case languagePackageTypes[pkgType]:
+		return category == "framework" || category == "lang"
+	case systemPackageTypes[pkgType]:
+		return category != "framework"
+	}
+	return true
+}
+
+// rankCandidates orders candidate products deterministically: by match method, then by
+// category fit, then by name. A product found by several methods keeps its best match.
+func rankCandidates(candidates []Product, pkgType string) []Product {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ri, rj := rankOf(candidates[i], pkgType), rankOf(candidates[j], pkgType)
+		if ri != rj {
+			if ri.method != rj.method {
+				return ri.method < rj.method
+			}
+			return ri.category < rj.category
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	ranked := candidates[:0]
+	seen := make(map[int64]bool)
+	for _, candidate := range candidates {
+		if seen[candidate.ID] {
+			continue
+		}
+		seen[candidate.ID] = true
+		ranked = append(ranked, candidate)
+	}
+	return ranked
+}
+
+// bestCandidate returns the first of ranked candidates, with the others recorded as its
+// alternatives. The match is ambiguous when the runner-up ranks as high.
+func bestCandidate(candidates []Product, pkgType string) *Product {
+	if len(candidates) == 0 {
+		return nil
+	}
+	best := candidates[0]
+	match := LookupMatch{}
+	if best.Match != nil {
+		match = *best.Match
+	}
+	if len(candidates) > 1 {
+		match.Alternatives = candidates[1:]
+		match.Ambiguous = rankOf(candidates[1], pkgType) == rankOf(best, pkgType)
+	}
+	best.Match = &match
+	return &best
+}
+
+// queryCandidates runs a query selecting the product columns followed by a matched
+// identifier, recording the match method each row was found by
+func (m *EOLDatabaseManager) queryCandidates(method func(identifier string) string, query string, args ...interface{}) ([]Product, error) {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []Product
+	for rows.Next() {
+		var product Product
+		var identifier string
+		if err := rows.Scan(&product.ID, &product.Name, &product.CategoryID, &product.CategoryName,
+			&product.Label, &product.Link, &product.VersionCommand, &product.Aliases, &product.Tags, &identifier); err != nil {
+			return nil, err
+		}
+		product.Match = &LookupMatch{Method: method(identifier), Identifier: identifier}
+		if product.Match.Method == "" {
+			continue
+		}
+		candidates = append(candidates, product)
+	}
+	return candidates, rows.Err()
+}
+
+// FindByPURL returns every product with a PURL identifier covering a package PURL,
+// ranked. Identifiers equal to the PURL's canonical form match exactly; others match
+// on type, namespace and name (purl_prefix).
+func (m *EOLDatabaseManager) FindByPURL(purl string) ([]Product, error) {
+	parsed, err := ParsePURL(purl)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := m.findPURLCandidates(parsed, func(identifier PURL) string {
+		return purlMatchMethod(identifier, parsed)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rankCandidates(candidates, parsed.Type), nil
+}
+
+// FindByPURLPrefix returns every product with a PURL identifier for a package type
+// (optionally with a namespace, as in "deb/debian" or "maven/org.springframework") and
+// name, whatever their version, ranked. The name must match in full: pkg:npm/react
+// does not match react-native. Names with a scope or module path ("@angular/core")
+// carry the rest of the namespace.
+func (m *EOLDatabaseManager) FindByPURLPrefix(purlType, packageName string) ([]Product, error) {
+	pkgPURL, ok := prefixPURL(purlType, packageName)
+	if !ok {
+		return nil, nil
+	}
+
+	candidates, err := m.findPURLCandidates(pkgPURL, func(PURL) string { return MatchMethodPURLPrefix })
+	if err != nil {
+		return nil, err
+	}
+	return rankCandidates(candidates, pkgPURL.Type), nil
+}
+
+// purlMatchMethod returns how an identifier matches a package PURL it covers: exactly
+// when they are the same canonical PURL, otherwise on type, namespace and name
+func purlMatchMethod(identifier, pkgPURL PURL) string {
+	if identifier.String() == pkgPURL.String() {
+		return MatchMethodPURL
+	}
+	return MatchMethodPURLPrefix
+}
+
+// prefixPURL builds the PURL of a package from a PURL type, optionally with a
+// namespace, and a package name that may carry the rest of the namespace
+func prefixPURL(purlType, packageName string) (PURL, bool) {
+	pkgPURL := PURL{Name: packageName}
+	pkgPURL.Type, pkgPURL.Namespace, _ = strings.Cut(purlType, "/")
+	if i := strings.LastIndex(packageName, "/"); i >= 0 {
+		pkgPURL.Namespace = strings.Trim(pkgPURL.Namespace+"/"+packageName[:i], "/")
+		pkgPURL.Name = packageName[i+1:]
+	}
+	if !validPURLType(pkgPURL.Type) || pkgPURL.Name == "" {
+		return pkgPURL, false
+	}
+	pkgPURL.Type = strings.ToLower(pkgPURL.Type)
+	return pkgPURL, true
+}
+
+// findPURLCandidates returns the products whose PURL identifiers share a package's
+// canonical key and cover it
+func (m *EOLDatabaseManager) findPURLCandidates(pkgPURL PURL, method func(identifier PURL) string) ([]Product, error) {
+	return m.queryCandidates(func(identifier string) string {
+		parsed, err := ParsePURL(identifier)
+		if err != nil || !parsed.Covers(pkgPURL) {
+			return ""
+		}
+		return method(parsed)
+	}, `
+		SELECT p.id, p.name, p.category_id, p.category_name, p.label, p.link, p.version_command, p.aliases, p.tags, i.identifier_value
+		FROM products p
+		JOIN identifiers i ON p.id = i.product_id
+		WHERE i.identifier_type = 'purl' AND i.canonical_value = ?
+	`, pkgPURL.Key())
+}
+
+// FindByCPE returns every product with a CPE identifier equal to a CPE or starting with
+// it, ranked. Supports both CPE 2.2 (cpe:/a:vendor:product) and CPE 2.3
+// (cpe:2.3:a:vendor:product) formats.
+func (m *EOLDatabaseManager) FindByCPE(cpeString string) ([]Product, error) {
+	candidates, err := m.queryCandidates(func(identifier string) string {
+		if strings.EqualFold(identifier, cpeString) {
+			return MatchMethodCPE
+		}
+		return MatchMethodCPEPrefix
+	}, `
+		SELECT p.id, p.name, p.category_id, p.category_name, p.label, p.link, p.version_command, p.aliases, p.tags, i.identifier_value
+		FROM products p
+		JOIN identifiers i ON p.id = i.product_id
+		WHERE i.identifier_type = 'cpe' AND LOWER(i.identifier_value) LIKE LOWER(?) ESCAPE '\'
+	`, escapeLike(cpeString)+"%")
+	if err != nil {
+		return nil, err
+	}
+	return rankCandidates(candidates, ""), nil
+}
+
+// FindByName returns every product whose name, alias or repology project matches a
+// package name, ranked with the package type deciding between equal matches
+func (m *EOLDatabaseManager) FindByName(name string, pkgType string) ([]Product, error) {
+	normalizedName := normalizePackageName(name)
+
+	lookups := []struct {
+		method string
+		query  string
+		arg    string
+	}{
+		{MatchMethodName, `
+			SELECT id, name, category_id, category_name, label, link, version_command, aliases, tags, name
+			FROM products WHERE LOWER(name) = LOWER(?)
+		`, normalizedName},
+		{MatchMethodAlias, `
+			SELECT id, name, category_id, category_name, label, link, version_command, aliases, tags, name
+			FROM products WHERE aliases LIKE ? ESCAPE '\'
+		`, "%\"" + escapeLike(normalizedName) + "\"%"},
+		{MatchMethodRepology, `
+			SELECT p.id, p.name, p.category_id, p.category_name, p.label, p.link, p.version_command, p.aliases, p.tags, i.identifier_value
+			FROM products p
+			JOIN identifiers i ON p.id = i.product_id
+			WHERE i.identifier_type = 'repology' AND LOWER(i.identifier_value) = LOWER(?)
+		`, normalizedName},
+	}
+
+	var candidates []Product
+	for _, lookup := range lookups {
+		found, err := m.queryCandidates(func(string) string { return lookup.method }, lookup.query, lookup.arg)
+		if err != nil {
+			return nil, err
+		}
+		for i := range found {
+			if lookup.method != MatchMethodRepology {
+				// Names and aliases are matched on the normalized package name
+				found[i].Match.Identifier = normalizedName
+			}
+		}
+		candidates = append(candidates, found...)
+	}
+	return rankCandidates(candidates, pkgType), nil
+}
+
+// escapeLike escapes the LIKE wildcards in a value, for patterns using ESCAPE '\'.
+// CPEs and package names often contain "_", which LIKE reads as any character.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// purlTypeOf returns the lowercase type of a PURL or of a "type/namespace" prefix
+func purlTypeOf(purl string) string {
+	purlType, _, _ := strings.Cut(strings.TrimPrefix(purl, "pkg:"), "/")
+	return strings.ToLower(purlType)
+}
+
+// lookupBest looks up the cycles of the best-ranked candidate
+func (m *EOLDatabaseManager) lookupBest(candidates []Product, pkgType string, err error) (*Product, []Cycle, error) {
+	if err != nil {
+		return nil, nil, err
+	}
+	product := bestCandidate(candidates, pkgType)
+	if product == nil {
+		return nil, nil, nil
+	}
+	cycles, err := m.GetProductCycles(product.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return product, cycles, nil
+}
@@ -0,0 +1,201 @@
+package db
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// newCandidatesTestDB creates a database with products sharing names and identifiers
+func newCandidatesTestDB(t *testing.T) *EOLDatabaseManager {
+	t.Helper()
+	manager, err := NewEOLDatabaseManager(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewEOLDatabaseManager() error = %v", err)
+	}
+	t.Cleanup(func() { manager.Close() })
+
+	products := []struct {
+		data        ProductData
+		identifiers []Identifier
+	}{
+		{ProductData{Name: "redis", Category: "database"}, []Identifier{{Type: "purl", ID: "pkg:generic/redis"}}},
+		{ProductData{Name: "redis-py", Category: "framework", Aliases: []string{"redis"}}, []Identifier{{Type: "purl", ID: "pkg:pypi/redis"}}},
+		{ProductData{Name: "valkey", Category: "database"}, []Identifier{{Type: "repology", ID: "redis"}}},
+		{ProductData{Name: "zulu", Category: "lang"}, []Identifier{{Type: "purl", ID: "pkg:generic/zulu"}}},
+		{ProductData{Name: "azul-zulu", Category: "lang"}, []Identifier{{Type: "purl", ID: "pkg:generic/zulu"}}},
+		{ProductData{Name: "mongo", Category: "database", Aliases: []string{"mongodb"}}, nil},
+		{ProductData{Name: "mongodb-driver", Category: "framework", Aliases: []string{"mongodb"}}, nil},
+	}
+	for _, p := range products {
+		productID, err := manager.UpsertProduct(p.data)
+		if err != nil {
+			t.Fatalf("UpsertProduct(%s) error = %v", p.data.Name, err)
+		}
+		if _, err := manager.UpsertIdentifiers(productID, p.identifiers); err != nil {
+			t.Fatalf("UpsertIdentifiers(%s) error = %v", p.data.Name, err)
+		}
+	}
+	return manager
+}
+
+// candidateNames returns the names of ranked candidates
+func candidateNames(candidates []Product) []string {
+	var names []string
+	for _, c := range candidates {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// TestFindByName tests that name lookups return every candidate, ranked
+func TestFindByName(t *testing.T) {
+	manager := newCandidatesTestDB(t)
+
+	tests := []struct {
+		name    string
+		pkgType string
+		want    []string
+		methods []string
+	}{
+		{"redis", "deb", []string{"redis", "redis-py", "valkey"}, []string{MatchMethodName, MatchMethodAlias, MatchMethodRepology}},
+		{"mongodb", "deb", []string{"mongo", "mongodb-driver"}, []string{MatchMethodAlias, MatchMethodAlias}},
+		{"mongodb", "python", []string{"mongodb-driver", "mongo"}, []string{MatchMethodAlias, MatchMethodAlias}},
+		{"mongodb", "", []string{"mongo", "mongodb-driver"}, []string{MatchMethodAlias, MatchMethodAlias}},
+		{"nonexistent", "deb", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.pkgType, func(t *testing.T) {
+			candidates, err := manager.FindByName(tt.name, tt.pkgType)
+			if err != nil {
+				t.Fatalf("FindByName() error = %v", err)
+			}
+			if got := candidateNames(candidates); !slices.Equal(got, tt.want) {
+				t.Errorf("FindByName() = %v, want %v", got, tt.want)
+			}
+			for i, c := range candidates {
+				if i < len(tt.methods) && c.Match.Method != tt.methods[i] {
+					t.Errorf("candidate %s matched by %s, want %s", c.Name, c.Match.Method, tt.methods[i])
+				}
+			}
+		})
+	}
+}
+
+// TestFindByPURL tests that PURL lookups return every product sharing an identifier
+func TestFindByPURL(t *testing.T) {
+	manager := newCandidatesTestDB(t)
+
+	candidates, err := manager.FindByPURL("pkg:generic/zulu@21.30.15")
+	if err != nil {
+		t.Fatalf("FindByPURL() error = %v", err)
+	}
+	if got := candidateNames(candidates); !slices.Equal(got, []string{"azul-zulu", "zulu"}) {
+		t.Errorf("FindByPURL() = %v, want [azul-zulu zulu]", got)
+	}
+	for _, c := range candidates {
+		if c.Match.Identifier != "pkg:generic/zulu" || c.Match.Method != MatchMethodPURLPrefix {
+			t.Errorf("candidate %s match = %+v, want purl_prefix via pkg:generic/zulu", c.Name, c.Match)
+		}
+	}
+
+	candidates, err = manager.FindByPURLPrefix("pypi", "redis")
+	if err != nil {
+		t.Fatalf("FindByPURLPrefix() error = %v", err)
+	}
+	if got := candidateNames(candidates); !slices.Equal(got, []string{"redis-py"}) {
+		t.Errorf("FindByPURLPrefix() = %v, want [redis-py]", got)
+	}
+}
+
+// TestLookupAmbiguity tests that lookups record alternatives and flag ties
+func TestLookupAmbiguity(t *testing.T) {
+	manager := newCandidatesTestDB(t)
+
+	tests := []struct {
+		name         string
+		lookup       func() (*Product, error)
+		want         string
+		alternatives []string
+		ambiguous    bool
+	}{
+		{
+			name: "name beats alias",
+			lookup: func() (*Product, error) {
+				p, _, err := manager.LookupByName("redis", "deb")
+				return p, err
+			},
+			want: "redis", alternatives: []string{"redis-py", "valkey"},
+		},
+		{
+			name: "category breaks an alias tie",
+			lookup: func() (*Product, error) {
+				p, _, err := manager.LookupByName("mongodb", "deb")
+				return p, err
+			},
+			want: "mongo", alternatives: []string{"mongodb-driver"},
+		},
+		{
+			name: "shared identifier",
+			lookup: func() (*Product, error) {
+				p, _, _, err := manager.LookupByPURL("pkg:generic/zulu@21.30.15")
+				return p, err
+			},
+			want: "azul-zulu", alternatives: []string{"zulu"}, ambiguous: true,
+		},
+		{
+			name: "single candidate",
+			lookup: func() (*Product, error) {
+				p, _, err := manager.LookupByPURLPrefix("pypi", "redis")
+				return p, err
+			},
+			want: "redis-py",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Repeated lookups resolve to the same product
+			for range 3 {
+				found, err := tt.lookup()
+				if err != nil {
+					t.Fatalf("lookup error = %v", err)
+				}
+				if found == nil || found.Name != tt.want {
+					t.Fatalf("lookup = %v, want %s", found, tt.want)
+				}
+				if got := candidateNames(found.Match.Alternatives); !slices.Equal(got, tt.alternatives) {
+					t.Errorf("Alternatives = %v, want %v", got, tt.alternatives)
+				}
+				if found.Match.Ambiguous != tt.ambiguous {
+					t.Errorf("Ambiguous = %v, want %v", found.Match.Ambiguous, tt.ambiguous)
+				}
+			}
+		})
+	}
+}
+
+// TestCategoryFits tests matching product categories to package types
+func TestCategoryFits(t *testing.T) {
+	tests := []struct {
+		category string
+		pkgType  string
+		want     bool
+	}{
+		{"framework", "python", true},
+		{"lang", "npm", true},
+		{"database", "python", false},
+		{"database", "deb", true},
+		{"framework", "deb", false},
+		{"os", "os", true},
+		{"framework", "", true},
+		{"server-app", "unknown-type", true},
+	}
+
+	for _, tt := range tests {
+		if got := categoryFits(tt.category, tt.pkgType); got != tt.want {
+			t.Errorf("categoryFits(%q, %q) = %v, want %v", tt.category, tt.pkgType, got, tt.want)
+		}
+	}
+}
@@ -64,18 +64,22 @@ type Identifier struct {
 
 // ReleaseData represents a release/cycle from the API
 type ReleaseData struct {
-	Name        string      `json:"name"`
-	Label       string      `json:"label"`
-	Codename    string      `json:"codename"`
-	ReleaseDate string      `json:"releaseDate"`
-	IsEol       *bool       `json:"isEol"`
-	EolFrom     string      `json:"eolFrom"`
-	IsEoas      *bool       `json:"isEoas"`
-	EoasFrom    string      `json:"eoasFrom"`
-	IsLts       bool        `json:"isLts"`
-	LtsFrom     string      `json:"ltsFrom"`
-	Latest      interface{} `json:"latest"`
-	IsMaintained bool       `json:"isMaintained"`
+	Name             string      `json:"name"`
+	Label            string      `json:"label"`
+	Codename         string      `json:"codename"`
+	ReleaseDate      string      `json:"releaseDate"`
+	IsEol            *bool       `json:"isEol"`
+	EolFrom          string      `json:"eolFrom"`
+	IsEoas           *bool       `json:"isEoas"`
+	EoasFrom         string      `json:"eoasFrom"`
+	IsLts            bool        `json:"isLts"`
+	LtsFrom          string      `json:"ltsFrom"`
+	IsEoes           *bool       `json:"isEoes"`
+	EoesFrom         string      `json:"eoesFrom"`
+	IsDiscontinued   *bool       `json:"isDiscontinued"`
+	DiscontinuedFrom string      `json:"discontinuedFrom"`
+	Latest           interface{} `json:"latest"`
+	IsMaintained     bool        `json:"isMaintained"`
 }
 
 // LatestInfo represents the latest version info
@@ -258,6 +262,7 @@ func (m *EOLDatabaseManager) initDatabase() error {
 			product_id INTEGER NOT NULL,
 			identifier_type TEXT NOT NULL,
 			identifier_value TEXT NOT NULL,
+			canonical_value TEXT,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (product_id) REFERENCES products(id),
@@ -289,6 +294,61 @@ func (m *EOLDatabaseManager) initDatabase() error {
 		}
 	}
 
+	if err := m.migrateCanonicalIdentifiers(); err != nil {
+		return fmt.Errorf("failed to migrate identifiers: %w", err)
+	}
+	if _, err := m.db.Exec(`CREATE INDEX IF NOT EXISTS idx_identifiers_canonical ON identifiers(canonical_value)`); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+// migrateCanonicalIdentifiers adds the canonical_value column to databases created
+// before PURLs were matched structurally, and fills it in for their PURL identifiers.
+// Keys stored by an earlier version that no longer match how keys are computed (e.g.
+// Maven names lowercased) are refreshed.
+func (m *EOLDatabaseManager) migrateCanonicalIdentifiers() error {
+	var count int
+	if err := m.db.QueryRow(`
+		SELECT COUNT(*) FROM pragma_table_info('identifiers') WHERE name = 'canonical_value'
+	`).Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		if _, err := m.db.Exec(`ALTER TABLE identifiers ADD COLUMN canonical_value TEXT`); err != nil {
+			return err
+		}
+	}
+
+	rows, err := m.db.Query(`
+		SELECT id, identifier_value, COALESCE(canonical_value, '') FROM identifiers WHERE identifier_type = 'purl'
+	`)
+	if err != nil {
+		return err
+	}
+	keys := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var value, stored string
+		if err := rows.Scan(&id, &value, &stored); err != nil {
+			rows.Close()
+			return err
+		}
+		if key := purlKey(value); key != stored {
+			keys[id] = key
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, key := range keys {
+		if _, err := m.db.Exec(`UPDATE identifiers SET canonical_value = ? WHERE id = ?`, sql.NullString{String: key, Valid: key != ""}, id); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -408,6 +468,36 @@ func (m *EOLDatabaseManager) UpsertCycle(productID int64, release ReleaseData) (
 		supportBool.Valid = true
 	}
 
+	// Parse extended support fields
+	var extendedDate sql.NullString
+	var extendedBool sql.NullInt64
+	if release.EoesFrom != "" {
+		extendedDate.String = release.EoesFrom
+		extendedDate.Valid = true
+	} else if release.IsEoes != nil {
+		if *release.IsEoes {
+			extendedBool.Int64 = 1
+		} else {
+			extendedBool.Int64 = 0
+		}
+		extendedBool.Valid = true
+	}
+
+	// Parse discontinued fields
+	var discontinuedDate sql.NullString
+	var discontinuedBool sql.NullInt64
+	if release.DiscontinuedFrom != "" {
+		discontinuedDate.String = release.DiscontinuedFrom
+		discontinuedDate.Valid = true
+	} else if release.IsDiscontinued != nil {
+		if *release.IsDiscontinued {
+			discontinuedBool.Int64 = 1
+		} else {
+			discontinuedBool.Int64 = 0
+		}
+		discontinuedBool.Valid = true
+	}
+
 	// Handle LTS
 	lts := 0
 	if release.IsLts {
@@ -445,8 +535,9 @@ func (m *EOLDatabaseManager) UpsertCycle(productID int64, release ReleaseData) (
 			product_id, cycle, cycle_label, codename, release_date,
 			eol, eol_boolean, latest_version, latest_release_date,
 			lts, lts_from, support, support_boolean,
+			discontinued, discontinued_boolean, extended_support, extended_support_boolean,
 			is_maintained, link, data_hash, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 		ON CONFLICT(product_id, cycle) DO UPDATE SET
 			cycle_label = excluded.cycle_label,
 			codename = excluded.codename,
@@ -459,6 +550,10 @@ func (m *EOLDatabaseManager) UpsertCycle(productID int64, release ReleaseData) (
 			lts_from = excluded.lts_from,
 			support = excluded.support,
 			support_boolean = excluded.support_boolean,
+			discontinued = excluded.discontinued,
+			discontinued_boolean = excluded.discontinued_boolean,
+			extended_support = excluded.extended_support,
+			extended_support_boolean = excluded.extended_support_boolean,
 			is_maintained = excluded.is_maintained,
 			link = excluded.link,
 			data_hash = excluded.data_hash,
@@ -466,12 +561,14 @@ func (m *EOLDatabaseManager) UpsertCycle(productID int64, release ReleaseData) (
 	`, productID, cycleName, release.Label, release.Codename, release.ReleaseDate,
 		eolDate, eolBool, latestVersion, latestDate,
 		lts, release.LtsFrom, supportDate, supportBool,
+		discontinuedDate, discontinuedBool, extendedDate, extendedBool,
 		isMaintained, latestLink, dataHash)
 
 	return err == nil, err
 }
 
-// UpsertIdentifiers inserts or updates identifiers for a product
+// UpsertIdentifiers inserts or updates identifiers for a product. PURL identifiers are
+// stored with their canonical match key; PURLs that do not parse never match.
 func (m *EOLDatabaseManager) UpsertIdentifiers(productID int64, identifiers []Identifier) (int, error) {
 	count := 0
 	for _, ident := range identifiers {
@@ -479,12 +576,18 @@ func (m *EOLDatabaseManager) UpsertIdentifiers(productID int64, identifiers []Id
 			continue
 		}
 
+		var canonical string
+		if ident.Type == "purl" {
+			canonical = purlKey(ident.ID)
+		}
+
 		_, err := m.db.Exec(`
-			INSERT INTO identifiers (product_id, identifier_type, identifier_value, updated_at)
-			VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+			INSERT INTO identifiers (product_id, identifier_type, identifier_value, canonical_value, updated_at)
+			VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
 			ON CONFLICT(product_id, identifier_type, identifier_value) DO UPDATE SET
+				canonical_value = excluded.canonical_value,
 				updated_at = CURRENT_TIMESTAMP
-		`, productID, ident.Type, ident.ID)
+		`, productID, ident.Type, ident.ID, sql.NullString{String: canonical, Valid: canonical != ""})
 		if err != nil {
 			return count, err
 		}
@@ -604,25 +707,49 @@ type Product struct {
 	VersionCommand sql.NullString
 	Aliases        sql.NullString
 	Tags           sql.NullString
+	Match          *LookupMatch // How a lookup found the product (nil outside lookups)
+}
+
+// Lookup match methods recorded on products returned by the lookup functions
+const (
+	MatchMethodPURL       = "purl"        // Exact PURL identifier
+	MatchMethodPURLPrefix = "purl_prefix" // PURL identifier matched on type, namespace and name
+	MatchMethodCPE        = "cpe"         // Exact CPE identifier
+	MatchMethodCPEPrefix  = "cpe_prefix"  // CPE identifier matched by prefix
+	MatchMethodName       = "name"        // Product name
+	MatchMethodAlias      = "alias"       // Product alias
+	MatchMethodRepology   = "repology"    // Repology project name
+)
+
+// LookupMatch records which identifier a lookup matched on
+type LookupMatch struct {
+	Method       string    // One of the MatchMethod constants
+	Identifier   string    // The identifier value that matched
+	Alternatives []Product // Other products the lookup matched, best ranked first
+	Ambiguous    bool      // The best alternative ranked as high as the product
 }
 
 // Cycle represents a release cycle from the database
 type Cycle struct {
-	ID                int64
-	ProductID         int64
-	Cycle             string
-	CycleLabel        sql.NullString
-	Codename          sql.NullString
-	ReleaseDate       sql.NullString
-	EOL               sql.NullString
-	EOLBoolean        sql.NullInt64
-	LatestVersion     sql.NullString
-	LatestReleaseDate sql.NullString
-	LTS               int
-	LTSFrom           sql.NullString
-	Support           sql.NullString
-	SupportBoolean    sql.NullInt64
-	IsMaintained      int
+	ID                     int64
+	ProductID              int64
+	Cycle                  string
+	CycleLabel             sql.NullString
+	Codename               sql.NullString
+	ReleaseDate            sql.NullString
+	EOL                    sql.NullString
+	EOLBoolean             sql.NullInt64
+	LatestVersion          sql.NullString
+	LatestReleaseDate      sql.NullString
+	LTS                    int
+	LTSFrom                sql.NullString
+	Support                sql.NullString
+	SupportBoolean         sql.NullInt64
+	Discontinued           sql.NullString
+	DiscontinuedBoolean    sql.NullInt64
+	ExtendedSupport        sql.NullString
+	ExtendedSupportBoolean sql.NullInt64
+	IsMaintained           int
 }
 
 // GetProductsByCategory returns products in a category
@@ -654,11 +781,13 @@ func (m *EOLDatabaseManager) GetProductCycles(productName string) ([]Cycle, erro
 	rows, err := m.db.Query(`
 		SELECT c.id, c.product_id, c.cycle, c.cycle_label, c.codename, c.release_date,
 			   c.eol, c.eol_boolean, c.latest_version, c.latest_release_date,
-			   c.lts, c.lts_from, c.support, c.support_boolean, c.is_maintained
+			   c.lts, c.lts_from, c.support, c.support_boolean,
+			   c.discontinued, c.discontinued_boolean, c.extended_support, c.extended_support_boolean,
+			   c.is_maintained
 		FROM cycles c
 		JOIN products p ON c.product_id = p.id
 		WHERE p.name = ?
-		ORDER BY c.release_date DESC
+		ORDER BY c.release_date DESC, c.id
 	`, productName)
 	if err != nil {
 		return nil, err
@@ -670,7 +799,9 @@ func (m *EOLDatabaseManager) GetProductCycles(productName string) ([]Cycle, erro
 		var c Cycle
 		if err := rows.Scan(&c.ID, &c.ProductID, &c.Cycle, &c.CycleLabel, &c.Codename,
 			&c.ReleaseDate, &c.EOL, &c.EOLBoolean, &c.LatestVersion, &c.LatestReleaseDate,
-			&c.LTS, &c.LTSFrom, &c.Support, &c.SupportBoolean, &c.IsMaintained); err != nil {
+			&c.LTS, &c.LTSFrom, &c.Support, &c.SupportBoolean,
+			&c.Discontinued, &c.DiscontinuedBoolean, &c.ExtendedSupport, &c.ExtendedSupportBoolean,
+			&c.IsMaintained); err != nil {
 			return nil, err
 		}
 		cycles = append(cycles, c)
@@ -754,7 +885,7 @@ func (m *EOLDatabaseManager) GetProductIdentifiers(productName string) ([]Produc
 		FROM identifiers i
 		JOIN products p ON i.product_id = p.id
 		WHERE p.name = ?
-		ORDER BY i.identifier_type
+		ORDER BY i.identifier_type, i.id
 	`, productName)
 	if err != nil {
 		return nil, err
@@ -772,45 +903,15 @@ func (m *EOLDatabaseManager) GetProductIdentifiers(productName string) ([]Produc
 	return identifiers, rows.Err()
 }
 
-// LookupByPURL looks up a product by its PURL identifier
+// LookupByPURL looks up a product by its PURL identifier. PURLs are compared
+// structurally: the identifier must name the same type, namespace and name, and any
+// version or qualifiers it sets must match. Other versions of the package match with
+// the purl_prefix method. When several products match, the best ranked wins (see
+// FindByPURL) and the others are recorded as alternatives.
 func (m *EOLDatabaseManager) LookupByPURL(purl string) (*Product, []Cycle, []ProductIdentifier, error) {
-	var product Product
-	err := m.db.QueryRow(`
-		SELECT p.id, p.name, p.category_id, p.category_name, p.label, p.link, p.version_command, p.aliases, p.tags
-		FROM products p
-		JOIN identifiers i ON p.id = i.product_id
-		WHERE i.identifier_type = 'purl' AND i.identifier_value = ?
-	`, purl).Scan(&product.ID, &product.Name, &product.CategoryID, &product.CategoryName,
-		&product.Label, &product.Link, &product.VersionCommand, &product.Aliases, &product.Tags)
-
-	if err == sql.ErrNoRows {
-		// Try partial match
-		purlBase := purl
-		for i := len(purl) - 1; i >= 0; i-- {
-			if purl[i] == '@' {
-				purlBase = purl[:i]
-				break
-			}
-		}
-
-		err = m.db.QueryRow(`
-			SELECT p.id, p.name, p.category_id, p.category_name, p.label, p.link, p.version_command, p.aliases, p.tags
-			FROM products p
-			JOIN identifiers i ON p.id = i.product_id
-			WHERE i.identifier_type = 'purl' AND i.identifier_value LIKE ?
-		`, purlBase+"%").Scan(&product.ID, &product.Name, &product.CategoryID, &product.CategoryName,
-			&product.Label, &product.Link, &product.VersionCommand, &product.Aliases, &product.Tags)
-	}
-
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil, nil, nil
-		}
-		return nil, nil, nil, err
-	}
-
-	cycles, err := m.GetProductCycles(product.Name)
-	if err != nil {
+	candidates, err := m.FindByPURL(purl)
+	product, cycles, err := m.lookupBest(candidates, purlTypeOf(purl), err)
+	if err != nil || product == nil {
 		return nil, nil, nil, err
 	}
 
@@ -819,7 +920,7 @@ func (m *EOLDatabaseManager) LookupByPURL(purl string) (*Product, []Cycle, []Pro
 		return nil, nil, nil, err
 	}
 
-	return &product, cycles, identifiers, nil
+	return product, cycles, identifiers, nil
 }
 
 // Stats represents database statistics
@@ -837,130 +938,26 @@ type Stats struct {
 	ProductsByCategory map[string]int
 }
 
-// LookupByCPE looks up a product by its CPE identifier
+// LookupByCPE looks up a product by its CPE identifier, exact matches first, then
+// identifiers starting with the CPE (a CPE without version)
 // Supports both CPE 2.2 (cpe:/a:vendor:product) and CPE 2.3 (cpe:2.3:a:vendor:product) formats
 func (m *EOLDatabaseManager) LookupByCPE(cpeString string) (*Product, []Cycle, error) {
-	var product Product
-
-	// Try exact match first
-	err := m.db.QueryRow(`
-		SELECT p.id, p.name, p.category_id, p.category_name, p.label, p.link, p.version_command, p.aliases, p.tags
-		FROM products p
-		JOIN identifiers i ON p.id = i.product_id
-		WHERE i.identifier_type = 'cpe' AND LOWER(i.identifier_value) = LOWER(?)
-	`, cpeString).Scan(&product.ID, &product.Name, &product.CategoryID, &product.CategoryName,
-		&product.Label, &product.Link, &product.VersionCommand, &product.Aliases, &product.Tags)
-
-	if err == sql.ErrNoRows {
-		// Try prefix match (CPE without version)
-		// Remove version from CPE for matching: cpe:2.3:a:vendor:product:* -> cpe:2.3:a:vendor:product
-		pattern := cpeString + "%"
-		err = m.db.QueryRow(`
-			SELECT p.id, p.name, p.category_id, p.category_name, p.label, p.link, p.version_command, p.aliases, p.tags
-			FROM products p
-			JOIN identifiers i ON p.id = i.product_id
-			WHERE i.identifier_type = 'cpe' AND LOWER(i.identifier_value) LIKE LOWER(?)
-		`, pattern).Scan(&product.ID, &product.Name, &product.CategoryID, &product.CategoryName,
-			&product.Label, &product.Link, &product.VersionCommand, &product.Aliases, &product.Tags)
-	}
-
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil, nil
-		}
-		return nil, nil, err
-	}
-
-	cycles, err := m.GetProductCycles(product.Name)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return &product, cycles, nil
+	candidates, err := m.FindByCPE(cpeString)
+	return m.lookupBest(candidates, "", err)
 }
 
-// LookupByPURLPrefix looks up a product by matching a PURL prefix pattern
-// For example, pkg:pypi/django would match pkg:pypi/django in the database
+// LookupByPURLPrefix looks up a product by the PURL type (optionally with a namespace)
+// and full name of a package, ignoring version and qualifiers (see FindByPURLPrefix)
 func (m *EOLDatabaseManager) LookupByPURLPrefix(purlType, packageName string) (*Product, []Cycle, error) {
-	var product Product
-
-	// Construct a pattern to match: pkg:<type>/<name> or pkg:<type>/%40<scope>/<name>
-	pattern := fmt.Sprintf("pkg:%s/%s%%", purlType, packageName)
-	patternWithScope := fmt.Sprintf("pkg:%s/%%/%s%%", purlType, packageName)
-
-	err := m.db.QueryRow(`
-		SELECT p.id, p.name, p.category_id, p.category_name, p.label, p.link, p.version_command, p.aliases, p.tags
-		FROM products p
-		JOIN identifiers i ON p.id = i.product_id
-		WHERE i.identifier_type = 'purl' AND (
-			LOWER(i.identifier_value) LIKE LOWER(?) OR
-			LOWER(i.identifier_value) LIKE LOWER(?)
-		)
-	`, pattern, patternWithScope).Scan(&product.ID, &product.Name, &product.CategoryID, &product.CategoryName,
-		&product.Label, &product.Link, &product.VersionCommand, &product.Aliases, &product.Tags)
-
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil, nil
-		}
-		return nil, nil, err
-	}
-
-	cycles, err := m.GetProductCycles(product.Name)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return &product, cycles, nil
+	candidates, err := m.FindByPURLPrefix(purlType, packageName)
+	return m.lookupBest(candidates, purlTypeOf(purlType), err)
 }
 
-// LookupByName looks up a product by name, checking product name, aliases, and repology identifiers
+// LookupByName looks up a product by name, checking product name, aliases, and repology
+// identifiers in that order. Products whose category suits the package type win ties.
 func (m *EOLDatabaseManager) LookupByName(name string, pkgType string) (*Product, []Cycle, error) {
-	var product Product
-
-	// Normalize the name for matching
-	normalizedName := normalizePackageName(name)
-
-	// Try exact product name match first
-	err := m.db.QueryRow(`
-		SELECT id, name, category_id, category_name, label, link, version_command, aliases, tags
-		FROM products WHERE LOWER(name) = LOWER(?)
-	`, normalizedName).Scan(&product.ID, &product.Name, &product.CategoryID, &product.CategoryName,
-		&product.Label, &product.Link, &product.VersionCommand, &product.Aliases, &product.Tags)
-
-	if err == sql.ErrNoRows {
-		// Try matching against aliases
-		err = m.db.QueryRow(`
-			SELECT id, name, category_id, category_name, label, link, version_command, aliases, tags
-			FROM products WHERE aliases LIKE ?
-		`, "%\""+normalizedName+"\"%").Scan(&product.ID, &product.Name, &product.CategoryID, &product.CategoryName,
-			&product.Label, &product.Link, &product.VersionCommand, &product.Aliases, &product.Tags)
-	}
-
-	if err == sql.ErrNoRows {
-		// Try matching via repology identifier
-		err = m.db.QueryRow(`
-			SELECT p.id, p.name, p.category_id, p.category_name, p.label, p.link, p.version_command, p.aliases, p.tags
-			FROM products p
-			JOIN identifiers i ON p.id = i.product_id
-			WHERE i.identifier_type = 'repology' AND LOWER(i.identifier_value) = LOWER(?)
-		`, normalizedName).Scan(&product.ID, &product.Name, &product.CategoryID, &product.CategoryName,
-			&product.Label, &product.Link, &product.VersionCommand, &product.Aliases, &product.Tags)
-	}
-
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil, nil
-		}
-		return nil, nil, err
-	}
-
-	cycles, err := m.GetProductCycles(product.Name)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return &product, cycles, nil
+	candidates, err := m.FindByName(name, pkgType)
+	return m.lookupBest(candidates, pkgType, err)
 }
 
 // normalizePackageName normalizes a package name for matching
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -353,6 +354,59 @@ func TestGetProductCycles(t *testing.T) {
 	}
 }
 
+// TestUpsertCycleExtendedSupport tests that extended support and discontinued fields are stored
+func TestUpsertCycleExtendedSupport(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	manager, err := NewEOLDatabaseManager(dbPath)
+	if err != nil {
+		t.Fatalf("NewEOLDatabaseManager() error = %v", err)
+	}
+	defer manager.Close()
+
+	product := ProductData{Name: "ubuntu", Category: "os"}
+	productID, _ := manager.UpsertProduct(product)
+
+	isEoes := false
+	isDiscontinued := true
+	releases := []ReleaseData{
+		{Name: "18.04", ReleaseDate: "2018-04-26", EolFrom: "2023-05-31", EoesFrom: "2028-04-01"},
+		{Name: "22.04", ReleaseDate: "2022-04-21", EolFrom: "2027-06-01", IsEoes: &isEoes, IsDiscontinued: &isDiscontinued},
+	}
+	for _, r := range releases {
+		if _, err := manager.UpsertCycle(productID, r); err != nil {
+			t.Fatalf("UpsertCycle() error = %v", err)
+		}
+	}
+
+	cycles, err := manager.GetProductCycles("ubuntu")
+	if err != nil {
+		t.Fatalf("GetProductCycles() error = %v", err)
+	}
+
+	byName := make(map[string]Cycle)
+	for _, c := range cycles {
+		byName[c.Cycle] = c
+	}
+
+	bionic := byName["18.04"]
+	if !bionic.ExtendedSupport.Valid || !strings.HasPrefix(bionic.ExtendedSupport.String, "2028-04-01") {
+		t.Errorf("18.04 ExtendedSupport = %v, want 2028-04-01", bionic.ExtendedSupport)
+	}
+	if bionic.ExtendedSupportBoolean.Valid {
+		t.Error("18.04 ExtendedSupportBoolean should be NULL when a date is set")
+	}
+
+	jammy := byName["22.04"]
+	if !jammy.ExtendedSupportBoolean.Valid || jammy.ExtendedSupportBoolean.Int64 != 0 {
+		t.Errorf("22.04 ExtendedSupportBoolean = %v, want 0", jammy.ExtendedSupportBoolean)
+	}
+	if !jammy.DiscontinuedBoolean.Valid || jammy.DiscontinuedBoolean.Int64 != 1 {
+		t.Errorf("22.04 DiscontinuedBoolean = %v, want 1", jammy.DiscontinuedBoolean)
+	}
+}
+
 // TestGetProductCyclesNotFound tests GetProductCycles for non-existent product
 func TestGetProductCyclesNotFound(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -567,6 +621,118 @@ func TestLookupByPURLPrefix(t *testing.T) {
 	_ = cycles
 }
 
+// TestLookupMatch tests that lookups record the identifier they matched on
+func TestLookupMatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	manager, err := NewEOLDatabaseManager(dbPath)
+	if err != nil {
+		t.Fatalf("NewEOLDatabaseManager() error = %v", err)
+	}
+	defer manager.Close()
+
+	productID, _ := manager.UpsertProduct(ProductData{
+		Name:     "postgresql",
+		Category: "database",
+		Aliases:  []string{"postgres"},
+	})
+	manager.UpsertIdentifiers(productID, []Identifier{
+		{Type: "purl", ID: "pkg:generic/postgresql"},
+		{Type: "cpe", ID: "cpe:2.3:a:postgresql:postgresql:*:*:*:*:*:*:*:*"},
+		{Type: "repology", ID: "postgresql-server"},
+	})
+
+	lookups := []struct {
+		name           string
+		lookup         func() (*Product, error)
+		wantMethod     string
+		wantIdentifier string
+	}{
+		{
+			name: "exact purl",
+			lookup: func() (*Product, error) {
+				p, _, _, err := manager.LookupByPURL("pkg:generic/postgresql")
+				return p, err
+			},
+			wantMethod:     MatchMethodPURL,
+			wantIdentifier: "pkg:generic/postgresql",
+		},
+		{
+			name: "purl without version",
+			lookup: func() (*Product, error) {
+				p, _, _, err := manager.LookupByPURL("pkg:generic/postgresql@16.1")
+				return p, err
+			},
+			wantMethod:     MatchMethodPURLPrefix,
+			wantIdentifier: "pkg:generic/postgresql",
+		},
+		{
+			name: "purl prefix",
+			lookup: func() (*Product, error) {
+				p, _, err := manager.LookupByPURLPrefix("generic", "postgresql")
+				return p, err
+			},
+			wantMethod:     MatchMethodPURLPrefix,
+			wantIdentifier: "pkg:generic/postgresql",
+		},
+		{
+			name: "cpe prefix",
+			lookup: func() (*Product, error) {
+				p, _, err := manager.LookupByCPE("cpe:2.3:a:postgresql:postgresql")
+				return p, err
+			},
+			wantMethod:     MatchMethodCPEPrefix,
+			wantIdentifier: "cpe:2.3:a:postgresql:postgresql:*:*:*:*:*:*:*:*",
+		},
+		{
+			name: "name",
+			lookup: func() (*Product, error) {
+				p, _, err := manager.LookupByName("postgresql", "database")
+				return p, err
+			},
+			wantMethod:     MatchMethodName,
+			wantIdentifier: "postgresql",
+		},
+		{
+			name: "alias",
+			lookup: func() (*Product, error) {
+				p, _, err := manager.LookupByName("postgres", "database")
+				return p, err
+			},
+			wantMethod:     MatchMethodAlias,
+			wantIdentifier: "postgres",
+		},
+		{
+			name: "repology",
+			lookup: func() (*Product, error) {
+				p, _, err := manager.LookupByName("postgresql-server", "database")
+				return p, err
+			},
+			wantMethod:     MatchMethodRepology,
+			wantIdentifier: "postgresql-server",
+		},
+	}
+
+	for _, tt := range lookups {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := tt.lookup()
+			if err != nil {
+				t.Fatalf("lookup error = %v", err)
+			}
+			if found == nil || found.Match == nil {
+				t.Fatal("lookup should return a product with match information")
+			}
+			if found.Match.Method != tt.wantMethod {
+				t.Errorf("Match.Method = %s, want %s", found.Match.Method, tt.wantMethod)
+			}
+			if found.Match.Identifier != tt.wantIdentifier {
+				t.Errorf("Match.Identifier = %s, want %s", found.Match.Identifier, tt.wantIdentifier)
+			}
+		})
+	}
+}
+
 // TestGetStats tests the GetStats method
 func TestGetStats(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -0,0 +1,323 @@
+package db
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ProductLookup looks up the product and cycles of a package. It is served by the
+// database or by an Index loaded from it.
+type ProductLookup interface {
+	LookupByPURL(purl string) (*Product, []Cycle, []ProductIdentifier, error)
+	LookupByPURLPrefix(purlType, packageName string) (*Product, []Cycle, error)
+	LookupByCPE(cpeString string) (*Product, []Cycle, error)
+	LookupByName(name string, pkgType string) (*Product, []Cycle, error)
+}
+
+// Index is an in-memory copy of the products, identifiers and cycles of the database.
+// It serves the same lookups as EOLDatabaseManager without a query per lookup, and
+// ranks candidates the same way. It also suggests products for packages no lookup
+// matched (see Suggest). An index does not see later changes to the database.
+type Index struct {
+	products    []Product
+	byName      map[string][]int             // Lowercase product name
+	byAlias     map[string][]int             // Lowercase alias
+	byPURLKey   map[string][]indexIdentifier // Canonical PURL match key
+	byRepology  map[string][]indexIdentifier // Lowercase repology project
+	cpes        []indexIdentifier            // Sorted by lowercase value, for prefix search
+	cycles      map[string][]Cycle           // Product name
+	identifiers map[string][]ProductIdentifier
+	terms       []suggestTerm // Names, aliases and identifiers products are suggested by
+	vendors     [][]string    // Vendor tokens, by product position
+}
+
+// indexIdentifier is an identifier of the product at a position of the index
+type indexIdentifier struct {
+	product int
+	value   string
+	lower   string
+	purl    PURL
+}
+
+// LoadIndex reads the products, identifiers and cycles of the database into memory
+func (m *EOLDatabaseManager) LoadIndex() (*Index, error) {
+	x := &Index{
+		byName:      make(map[string][]int),
+		byAlias:     make(map[string][]int),
+		byPURLKey:   make(map[string][]indexIdentifier),
+		byRepology:  make(map[string][]indexIdentifier),
+		cycles:      make(map[string][]Cycle),
+		identifiers: make(map[string][]ProductIdentifier),
+	}
+
+	if err := x.loadProducts(m); err != nil {
+		return nil, err
+	}
+	if err := x.loadIdentifiers(m); err != nil {
+		return nil, err
+	}
+	if err := x.loadCycles(m); err != nil {
+		return nil, err
+	}
+	x.loadSuggestTerms()
+	return x, nil
+}
+
+// loadProducts reads every product and indexes it by name and alias
+func (x *Index) loadProducts(m *EOLDatabaseManager) error {
+	rows, err := m.db.Query(`
+		SELECT id, name, category_id, category_name, label, link, version_command, aliases, tags
+		FROM products ORDER BY id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p Product
+		if err := rows.Scan(&p.ID, &p.Name, &p.CategoryID, &p.CategoryName,
+			&p.Label, &p.Link, &p.VersionCommand, &p.Aliases, &p.Tags); err != nil {
+			return err
+		}
+		i := len(x.products)
+		x.products = append(x.products, p)
+		x.byName[strings.ToLower(p.Name)] = append(x.byName[strings.ToLower(p.Name)], i)
+
+		var aliases []string
+		if p.Aliases.Valid && json.Unmarshal([]byte(p.Aliases.String), &aliases) == nil {
+			for _, alias := range aliases {
+				key := strings.ToLower(alias)
+				if !slices.Contains(x.byAlias[key], i) {
+					x.byAlias[key] = append(x.byAlias[key], i)
+				}
+			}
+		}
+	}
+	return rows.Err()
+}
+
+// loadIdentifiers reads every identifier and indexes PURLs by match key, CPEs for prefix
+// search and repology projects by name
+func (x *Index) loadIdentifiers(m *EOLDatabaseManager) error {
+	positions := make(map[int64]int, len(x.products))
+	for i, p := range x.products {
+		positions[p.ID] = i
+	}
+
+	rows, err := m.db.Query(`
+		SELECT product_id, identifier_type, identifier_value
+		FROM identifiers ORDER BY identifier_type, id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int64
+		var id ProductIdentifier
+		if err := rows.Scan(&productID, &id.Type, &id.Value); err != nil {
+			return err
+		}
+		i, ok := positions[productID]
+		if !ok {
+			continue
+		}
+		name := x.products[i].Name
+		x.identifiers[name] = append(x.identifiers[name], id)
+
+		identifier := indexIdentifier{product: i, value: id.Value, lower: strings.ToLower(id.Value)}
+		switch id.Type {
+		case "purl":
+			parsed, err := ParsePURL(id.Value)
+			if err != nil {
+				continue
+			}
+			identifier.purl = parsed
+			x.byPURLKey[parsed.Key()] = append(x.byPURLKey[parsed.Key()], identifier)
+		case "cpe":
+			x.cpes = append(x.cpes, identifier)
+		case "repology":
+			x.byRepology[identifier.lower] = append(x.byRepology[identifier.lower], identifier)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	sort.SliceStable(x.cpes, func(i, j int) bool { return x.cpes[i].lower < x.cpes[j].lower })
+	return nil
+}
+
+// loadCycles reads every cycle, in the order GetProductCycles returns them
+func (x *Index) loadCycles(m *EOLDatabaseManager) error {
+	rows, err := m.db.Query(`
+		SELECT p.name, c.id, c.product_id, c.cycle, c.cycle_label, c.codename, c.release_date,
+			   c.eol, c.eol_boolean, c.latest_version, c.latest_release_date,
+			   c.lts, c.lts_from, c.support, c.support_boolean,
+			   c.discontinued, c.discontinued_boolean, c.extended_support, c.extended_support_boolean,
+			   c.is_maintained
+		FROM cycles c
+		JOIN products p ON c.product_id = p.id
+		ORDER BY p.name, c.release_date DESC, c.id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var c Cycle
+		if err := rows.Scan(&name, &c.ID, &c.ProductID, &c.Cycle, &c.CycleLabel, &c.Codename,
+			&c.ReleaseDate, &c.EOL, &c.EOLBoolean, &c.LatestVersion, &c.LatestReleaseDate,
+			&c.LTS, &c.LTSFrom, &c.Support, &c.SupportBoolean,
+			&c.Discontinued, &c.DiscontinuedBoolean, &c.ExtendedSupport, &c.ExtendedSupportBoolean,
+			&c.IsMaintained); err != nil {
+			return err
+		}
+		x.cycles[name] = append(x.cycles[name], c)
+	}
+	return rows.Err()
+}
+
+// candidate returns a copy of the product at a position with how it matched
+func (x *Index) candidate(i int, method, identifier string) Product {
+	p := x.products[i]
+	p.Match = &LookupMatch{Method: method, Identifier: identifier}
+	return p
+}
+
+// FindByPURL returns every product with a PURL identifier covering a package PURL,
+// ranked as EOLDatabaseManager.FindByPURL does
+func (x *Index) FindByPURL(purl string) ([]Product, error) {
+	parsed, err := ParsePURL(purl)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []Product
+	for _, identifier := range x.byPURLKey[parsed.Key()] {
+		if identifier.purl.Covers(parsed) {
+			candidates = append(candidates, x.candidate(identifier.product, purlMatchMethod(identifier.purl, parsed), identifier.value))
+		}
+	}
+	return rankCandidates(candidates, parsed.Type), nil
+}
+
+// FindByPURLPrefix returns every product with a PURL identifier for a package type and
+// name, ranked as EOLDatabaseManager.FindByPURLPrefix does
+func (x *Index) FindByPURLPrefix(purlType, packageName string) ([]Product, error) {
+	pkgPURL, ok := prefixPURL(purlType, packageName)
+	if !ok {
+		return nil, nil
+	}
+	var candidates []Product
+	for _, identifier := range x.byPURLKey[pkgPURL.Key()] {
+		if identifier.purl.Covers(pkgPURL) {
+			candidates = append(candidates, x.candidate(identifier.product, MatchMethodPURLPrefix, identifier.value))
+		}
+	}
+	return rankCandidates(candidates, pkgPURL.Type), nil
+}
+
+// FindByCPE returns every product with a CPE identifier equal to a CPE or starting with
+// it, ranked as EOLDatabaseManager.FindByCPE does
+func (x *Index) FindByCPE(cpeString string) ([]Product, error) {
+	prefix := strings.ToLower(cpeString)
+	start := sort.Search(len(x.cpes), func(i int) bool { return x.cpes[i].lower >= prefix })
+
+	var candidates []Product
+	for _, identifier := range x.cpes[start:] {
+		if !strings.HasPrefix(identifier.lower, prefix) {
+			break
+		}
+		method := MatchMethodCPEPrefix
+		if identifier.lower == prefix {
+			method = MatchMethodCPE
+		}
+		candidates = append(candidates, x.candidate(identifier.product, method, identifier.value))
+	}
+	return rankCandidates(candidates, ""), nil
+}
+
+// FindByName returns every product whose name, alias or repology project matches a
+// package name, ranked as EOLDatabaseManager.FindByName does
+func (x *Index) FindByName(name string, pkgType string) ([]Product, error) {
+	normalizedName := normalizePackageName(name)
+	key := strings.ToLower(normalizedName)
+
+	var candidates []Product
+	for _, i := range x.byName[key] {
+		candidates = append(candidates, x.candidate(i, MatchMethodName, normalizedName))
+	}
+	for _, i := range x.byAlias[key] {
+		candidates = append(candidates, x.candidate(i, MatchMethodAlias, normalizedName))
+	}
+	for _, identifier := range x.byRepology[key] {
+		candidates = append(candidates, x.candidate(identifier.product, MatchMethodRepology, identifier.value))
+	}
+	return rankCandidates(candidates, pkgType), nil
+}
+
+// GetProductCycles returns the cycles of a product, newest release first
+func (x *Index) GetProductCycles(productName string) ([]Cycle, error) {
+	return slices.Clone(x.cycles[productName]), nil
+}
+
+// GetProductIdentifiers returns the identifiers of a product
+func (x *Index) GetProductIdentifiers(productName string) ([]ProductIdentifier, error) {
+	return slices.Clone(x.identifiers[productName]), nil
+}
+
+// LookupByPURL looks up a product by its PURL identifier (see
+// EOLDatabaseManager.LookupByPURL)
+func (x *Index) LookupByPURL(purl string) (*Product, []Cycle, []ProductIdentifier, error) {
+	candidates, err := x.FindByPURL(purl)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	product := bestCandidate(candidates, purlTypeOf(purl))
+	if product == nil {
+		return nil, nil, nil, nil
+	}
+	cycles, _ := x.GetProductCycles(product.Name)
+	identifiers, _ := x.GetProductIdentifiers(product.Name)
+	return product, cycles, identifiers, nil
+}
+
+// LookupByPURLPrefix looks up a product by the PURL type and full name of a package (see
+// EOLDatabaseManager.LookupByPURLPrefix)
+func (x *Index) LookupByPURLPrefix(purlType, packageName string) (*Product, []Cycle, error) {
+	candidates, err := x.FindByPURLPrefix(purlType, packageName)
+	return x.lookupBest(candidates, purlTypeOf(purlType), err)
+}
+
+// LookupByCPE looks up a product by its CPE identifier (see
+// EOLDatabaseManager.LookupByCPE)
+func (x *Index) LookupByCPE(cpeString string) (*Product, []Cycle, error) {
+	candidates, err := x.FindByCPE(cpeString)
+	return x.lookupBest(candidates, "", err)
+}
+
+// LookupByName looks up a product by name, alias or repology project (see
+// EOLDatabaseManager.LookupByName)
+func (x *Index) LookupByName(name string, pkgType string) (*Product, []Cycle, error) {
+	candidates, err := x.FindByName(name, pkgType)
+	return x.lookupBest(candidates, pkgType, err)
+}
+
+// lookupBest returns the best-ranked candidate and its cycles
+func (x *Index) lookupBest(candidates []Product, pkgType string, err error) (*Product, []Cycle, error) {
+	if err != nil {
+		return nil, nil, err
+	}
+	product := bestCandidate(candidates, pkgType)
+	if product == nil {
+		return nil, nil, nil
+	}
+	cycles, _ := x.GetProductCycles(product.Name)
+	return product, cycles, nil
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// newIndexTestDB extends the candidates database with CPE and versioned PURL
+// identifiers and cycles
+func newIndexTestDB(t *testing.T) *EOLDatabaseManager {
+	t.Helper()
+	manager := newCandidatesTestDB(t)
+
+	products := []struct {
+		data        ProductData
+		identifiers []Identifier
+		releases    []string
+	}{
+		{ProductData{Name: "nginx", Category: "server-app"}, []Identifier{
+			{Type: "cpe", ID: "cpe:2.3:a:f5:nginx"},
+			{Type: "purl", ID: "pkg:deb/debian/nginx"},
+		}, []string{"1.24", "1.26", "1.25"}},
+		{ProductData{Name: "nginx-plus", Category: "server-app"}, []Identifier{
+			{Type: "cpe", ID: "cpe:2.3:a:f5:nginx_plus"},
+		}, []string{"r30"}},
+		{ProductData{Name: "spring-framework", Category: "framework"}, []Identifier{
+			{Type: "purl", ID: "pkg:maven/org.springframework/spring-core"},
+			{Type: "purl", ID: "pkg:maven/org.springframework/spring-core@6.1.0"},
+		}, []string{"6.1", "6.0"}},
+	}
+	for _, p := range products {
+		productID, err := manager.UpsertProduct(p.data)
+		if err != nil {
+			t.Fatalf("UpsertProduct(%s) error = %v", p.data.Name, err)
+		}
+		if _, err := manager.UpsertIdentifiers(productID, p.identifiers); err != nil {
+			t.Fatalf("UpsertIdentifiers(%s) error = %v", p.data.Name, err)
+		}
+		for i, release := range p.releases {
+			if _, err := manager.UpsertCycle(productID, ReleaseData{Name: release, ReleaseDate: fmt.Sprintf("2023-%02d-01", i+1)}); err != nil {
+				t.Fatalf("UpsertCycle(%s %s) error = %v", p.data.Name, release, err)
+			}
+		}
+	}
+	return manager
+}
+
+// TestIndexMatchesDatabase tests that lookups served from the index return the same
+// products, matches, alternatives and cycles as the database
+func TestIndexMatchesDatabase(t *testing.T) {
+	manager := newIndexTestDB(t)
+	index, err := manager.LoadIndex()
+	if err != nil {
+		t.Fatalf("LoadIndex() error = %v", err)
+	}
+
+	type lookup func(ProductLookup) (*Product, []Cycle, error)
+	byName := func(name, pkgType string) lookup {
+		return func(l ProductLookup) (*Product, []Cycle, error) { return l.LookupByName(name, pkgType) }
+	}
+	byPURLPrefix := func(purlType, name string) lookup {
+		return func(l ProductLookup) (*Product, []Cycle, error) { return l.LookupByPURLPrefix(purlType, name) }
+	}
+	byCPE := func(cpe string) lookup {
+		return func(l ProductLookup) (*Product, []Cycle, error) { return l.LookupByCPE(cpe) }
+	}
+	byPURL := func(purl string) lookup {
+		return func(l ProductLookup) (*Product, []Cycle, error) {
+			product, cycles, _, err := l.LookupByPURL(purl)
+			return product, cycles, err
+		}
+	}
+
+	tests := []struct {
+		name   string
+		lookup lookup
+	}{
+		{"name with alias and repology candidates", byName("redis", "deb")},
+		{"ambiguous aliases", byName("mongodb", "")},
+		{"aliases ranked by package type", byName("mongodb", "python")},
+		{"name with cycles", byName("nginx", "binary")},
+		{"name case", byName("NGINX", "")},
+		{"unknown name", byName("nonexistent", "deb")},
+		{"exact cpe", byCPE("cpe:2.3:a:f5:nginx")},
+		{"cpe prefix", byCPE("cpe:2.3:a:f5:")},
+		{"cpe underscore is literal", byCPE("cpe:2.3:a:f5:nginx_")},
+		{"unknown cpe", byCPE("cpe:2.3:a:apache:httpd")},
+		{"purl", byPURL("pkg:deb/debian/nginx@1.24.0-1")},
+		{"purl pinned version", byPURL("pkg:maven/org.springframework/spring-core@6.1.0")},
+		{"purl other version", byPURL("pkg:maven/org.springframework/spring-core@6.0.9")},
+		{"purl shared identifier", byPURL("pkg:generic/zulu@21")},
+		{"purl prefix", byPURLPrefix("maven", "org.springframework/spring-core")},
+		{"purl prefix with namespace", byPURLPrefix("deb/debian", "nginx")},
+		{"purl prefix partial name", byPURLPrefix("pypi", "redi")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantProduct, wantCycles, wantErr := tt.lookup(manager)
+			gotProduct, gotCycles, gotErr := tt.lookup(index)
+			if (gotErr != nil) != (wantErr != nil) {
+				t.Fatalf("index error = %v, database error = %v", gotErr, wantErr)
+			}
+			if !reflect.DeepEqual(gotProduct, wantProduct) {
+				t.Errorf("index product = %+v, database product = %+v", gotProduct, wantProduct)
+			}
+			if !reflect.DeepEqual(gotCycles, wantCycles) {
+				t.Errorf("index cycles = %+v, database cycles = %+v", gotCycles, wantCycles)
+			}
+		})
+	}
+}
+
+// TestIndexSnapshot tests that an index keeps serving the data it was loaded with
+func TestIndexSnapshot(t *testing.T) {
+	manager := newIndexTestDB(t)
+	index, err := manager.LoadIndex()
+	if err != nil {
+		t.Fatalf("LoadIndex() error = %v", err)
+	}
+	if _, err := manager.UpsertProduct(ProductData{Name: "haproxy", Category: "server-app"}); err != nil {
+		t.Fatalf("UpsertProduct() error = %v", err)
+	}
+
+	if product, _, _ := index.LookupByName("haproxy", ""); product != nil {
+		t.Errorf("index found %s added after loading", product.Name)
+	}
+	if product, _, _ := manager.LookupByName("haproxy", ""); product == nil {
+		t.Error("database did not find haproxy")
+	}
+}
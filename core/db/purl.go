@@ -0,0 +1,226 @@
+package db
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// PURL is a package URL parsed into its components
+// (pkg:type/namespace/name@version?qualifiers#subpath)
+type PURL struct {
+	Type       string
+	Namespace  string
+	Name       string
+	Version    string
+	Qualifiers map[string]string
+	Subpath    string
+}
+
+// lowercasePURLTypes are the PURL types whose namespace and name are case insensitive
+var lowercasePURLTypes = map[string]bool{
+	"alpm":      true,
+	"apk":       true,
+	"bitbucket": true,
+	"composer":  true,
+	"deb":       true,
+	"github":    true,
+	"hex":       true,
+	"npm":       true,
+	"pypi":      true,
+}
+
+// ParsePURL strictly parses a package URL. The namespace, name and version are
+// percent-decoded, and an unencoded "@" is only read as the version separator when it
+// follows the last "/" (so pkg:npm/@angular/core has a scope rather than a version).
+func ParsePURL(purl string) (PURL, error) {
+	var p PURL
+
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return p, fmt.Errorf("invalid PURL %q: missing pkg: scheme", purl)
+	}
+	rest = strings.TrimLeft(rest, "/")
+
+	if before, subpath, ok := strings.Cut(rest, "#"); ok {
+		rest = before
+		p.Subpath = strings.Trim(subpath, "/")
+	}
+	if before, qualifiers, ok := strings.Cut(rest, "?"); ok {
+		rest = before
+		parsed, err := parsePURLQualifiers(qualifiers)
+		if err != nil {
+			return p, fmt.Errorf("invalid PURL %q: %w", purl, err)
+		}
+		p.Qualifiers = parsed
+	}
+
+	purlType, path, ok := strings.Cut(rest, "/")
+	if !ok || !validPURLType(purlType) {
+		return p, fmt.Errorf("invalid PURL %q: missing or invalid type", purl)
+	}
+	p.Type = strings.ToLower(purlType)
+
+	path = strings.Trim(path, "/")
+	if at := strings.LastIndex(path, "@"); at >= 0 && at > strings.LastIndex(path, "/") {
+		version, err := url.PathUnescape(path[at+1:])
+		if err != nil {
+			return p, fmt.Errorf("invalid PURL %q: %w", purl, err)
+		}
+		p.Version = version
+		path = path[:at]
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		decoded, err := url.PathUnescape(segment)
+		if err != nil {
+			return p, fmt.Errorf("invalid PURL %q: %w", purl, err)
+		}
+		segments[i] = decoded
+	}
+	p.Name = segments[len(segments)-1]
+	if p.Name == "" {
+		return p, fmt.Errorf("invalid PURL %q: missing name", purl)
+	}
+	p.Namespace = strings.Join(segments[:len(segments)-1], "/")
+
+	return p, nil
+}
+
+// validPURLType reports whether a PURL type is made of ASCII letters, digits, ".", "+"
+// and "-" and does not start with a digit
+func validPURLType(purlType string) bool {
+	if purlType == "" || (purlType[0] >= '0' && purlType[0] <= '9') {
+		return false
+	}
+	for _, r := range purlType {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '+', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// parsePURLQualifiers parses the key=value pairs of a PURL's qualifiers. Keys are case
+// insensitive and qualifiers with an empty value are dropped.
+func parsePURLQualifiers(qualifiers string) (map[string]string, error) {
+	parsed := make(map[string]string)
+	for _, pair := range strings.Split(qualifiers, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("malformed qualifier %q", pair)
+		}
+		decoded, err := url.QueryUnescape(value)
+		if err != nil {
+			return nil, fmt.Errorf("malformed qualifier %q: %w", pair, err)
+		}
+		if decoded != "" {
+			parsed[strings.ToLower(key)] = decoded
+		}
+	}
+	return parsed, nil
+}
+
+// normalized returns the PURL with the type-specific normalization of its namespace and
+// name applied: lowercase for case-insensitive types, and "-" for "_" in PyPI names
+func (p PURL) normalized() PURL {
+	if lowercasePURLTypes[p.Type] {
+		p.Namespace = strings.ToLower(p.Namespace)
+		p.Name = strings.ToLower(p.Name)
+	}
+	if p.Type == "pypi" {
+		p.Name = strings.ReplaceAll(p.Name, "_", "-")
+	}
+	return p
+}
+
+// String returns the canonical form of a PURL: normalized namespace and name, encoded
+// components and qualifiers sorted by key
+func (p PURL) String() string {
+	p = p.normalized()
+
+	var b strings.Builder
+	b.WriteString("pkg:")
+	b.WriteString(p.Type)
+	b.WriteString("/")
+	b.WriteString(p.path())
+	if p.Version != "" {
+		b.WriteString("@")
+		b.WriteString(url.PathEscape(p.Version))
+	}
+	if len(p.Qualifiers) > 0 {
+		keys := make([]string, 0, len(p.Qualifiers))
+		for key := range p.Qualifiers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for i, key := range keys {
+			if i == 0 {
+				b.WriteString("?")
+			} else {
+				b.WriteString("&")
+			}
+			b.WriteString(key + "=" + url.QueryEscape(p.Qualifiers[key]))
+		}
+	}
+	if p.Subpath != "" {
+		b.WriteString("#")
+		b.WriteString(p.Subpath)
+	}
+	return b.String()
+}
+
+// Key returns the form PURLs are matched on: the canonical type, namespace and name.
+// Only types the PURL spec defines as case insensitive are lowercased, so Maven, Go and
+// NuGet names keep their case. It is stored alongside PURL identifiers in the database.
+func (p PURL) Key() string {
+	p = p.normalized()
+	return "pkg:" + p.Type + "/" + p.path()
+}
+
+// path returns the encoded namespace and name of a PURL
+func (p PURL) path() string {
+	var segments []string
+	if p.Namespace != "" {
+		segments = strings.Split(p.Namespace, "/")
+	}
+	segments = append(segments, p.Name)
+	for i, segment := range segments {
+		// "@" is escaped so that it cannot be mistaken for the version separator
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "@", "%40")
+	}
+	return strings.Join(segments, "/")
+}
+
+// Covers reports whether an identifier PURL applies to a package PURL: both name the
+// same package, and the version and qualifiers the identifier sets match the package's
+func (p PURL) Covers(pkg PURL) bool {
+	if p.Key() != pkg.Key() {
+		return false
+	}
+	if p.Version != "" && p.Version != pkg.Version {
+		return false
+	}
+	for key, value := range p.Qualifiers {
+		if pkg.Qualifiers[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// purlKey returns the match key of a PURL identifier, or "" if it does not parse
+func purlKey(purl string) string {
+	p, err := ParsePURL(purl)
+	if err != nil {
+		return ""
+	}
+	return p.Key()
+}
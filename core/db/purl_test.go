@@ -0,0 +1,267 @@
+package db
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestParsePURL tests strict parsing of package URLs
+func TestParsePURL(t *testing.T) {
+	tests := []struct {
+		purl    string
+		want    PURL
+		wantErr bool
+	}{
+		{
+			purl: "pkg:pypi/django@4.2.1",
+			want: PURL{Type: "pypi", Name: "django", Version: "4.2.1"},
+		},
+		{
+			purl: "pkg:npm/%40angular/core@16.2.0",
+			want: PURL{Type: "npm", Namespace: "@angular", Name: "core", Version: "16.2.0"},
+		},
+		{
+			purl: "pkg:npm/@angular/core",
+			want: PURL{Type: "npm", Namespace: "@angular", Name: "core"},
+		},
+		{
+			purl: "pkg:deb/debian/openssl@3.0.11-1~deb12u2?arch=amd64&distro=debian-12",
+			want: PURL{Type: "deb", Namespace: "debian", Name: "openssl", Version: "3.0.11-1~deb12u2",
+				Qualifiers: map[string]string{"arch": "amd64", "distro": "debian-12"}},
+		},
+		{
+			purl: "pkg:golang/github.com/gin-gonic/gin@v1.9.1#binding",
+			want: PURL{Type: "golang", Namespace: "github.com/gin-gonic", Name: "gin", Version: "v1.9.1", Subpath: "binding"},
+		},
+		{purl: "npm/react", wantErr: true},
+		{purl: "pkg:react", wantErr: true},
+		{purl: "pkg:/react", wantErr: true},
+		{purl: "pkg:1npm/react", wantErr: true},
+		{purl: "pkg:npm/", wantErr: true},
+		{purl: "pkg:npm/react%zz", wantErr: true},
+		{purl: "pkg:npm/react?arch", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.purl, func(t *testing.T) {
+			got, err := ParsePURL(tt.purl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Type != tt.want.Type || got.Namespace != tt.want.Namespace || got.Name != tt.want.Name ||
+				got.Version != tt.want.Version || got.Subpath != tt.want.Subpath {
+				t.Errorf("ParsePURL() = %+v, want %+v", got, tt.want)
+			}
+			if len(got.Qualifiers) != len(tt.want.Qualifiers) {
+				t.Errorf("ParsePURL() qualifiers = %v, want %v", got.Qualifiers, tt.want.Qualifiers)
+			}
+			for key, value := range tt.want.Qualifiers {
+				if got.Qualifiers[key] != value {
+					t.Errorf("ParsePURL() qualifier %s = %q, want %q", key, got.Qualifiers[key], value)
+				}
+			}
+		})
+	}
+}
+
+// TestPURLCanonicalForm tests the canonical string and match key of PURLs
+func TestPURLCanonicalForm(t *testing.T) {
+	tests := []struct {
+		purl      string
+		canonical string
+		key       string
+	}{
+		{"pkg:pypi/Flask_SQLAlchemy@3.1.1", "pkg:pypi/flask-sqlalchemy@3.1.1", "pkg:pypi/flask-sqlalchemy"},
+		{"pkg:npm/@angular/core@16.2.0", "pkg:npm/%40angular/core@16.2.0", "pkg:npm/%40angular/core"},
+		{"pkg:DEB/Debian/OpenSSL?distro=debian-12&arch=amd64", "pkg:deb/debian/openssl?arch=amd64&distro=debian-12", "pkg:deb/debian/openssl"},
+		{"pkg:maven/org.springframework/spring-core@6.1.0", "pkg:maven/org.springframework/spring-core@6.1.0", "pkg:maven/org.springframework/spring-core"},
+		{"pkg:nuget/Microsoft.AspNetCore.App", "pkg:nuget/Microsoft.AspNetCore.App", "pkg:nuget/Microsoft.AspNetCore.App"},
+		{"pkg:golang/github.com/Azure/azure-sdk-for-go@v68.0.0", "pkg:golang/github.com/Azure/azure-sdk-for-go@v68.0.0", "pkg:golang/github.com/Azure/azure-sdk-for-go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.purl, func(t *testing.T) {
+			p, err := ParsePURL(tt.purl)
+			if err != nil {
+				t.Fatalf("ParsePURL() error = %v", err)
+			}
+			if got := p.String(); got != tt.canonical {
+				t.Errorf("String() = %q, want %q", got, tt.canonical)
+			}
+			if got := p.Key(); got != tt.key {
+				t.Errorf("Key() = %q, want %q", got, tt.key)
+			}
+			// The canonical form parses back to itself
+			reparsed, err := ParsePURL(p.String())
+			if err != nil || reparsed.String() != tt.canonical {
+				t.Errorf("ParsePURL(String()) = %q (%v), want %q", reparsed.String(), err, tt.canonical)
+			}
+		})
+	}
+}
+
+// purlCollision is an entry of the PURL collision corpus: an identifier in the database
+// and a package looked up by PURL or by type and name
+type purlCollision struct {
+	Note       string `json:"note"`
+	Identifier string `json:"identifier"`
+	PURL       string `json:"purl"`
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	Match      bool   `json:"match"`
+}
+
+// TestPURLCollisionCorpus tests PURL lookups against known prefix-match collisions
+func TestPURLCollisionCorpus(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "purl_collisions.json"))
+	if err != nil {
+		t.Fatalf("failed to read corpus: %v", err)
+	}
+	var corpus []purlCollision
+	if err := json.Unmarshal(data, &corpus); err != nil {
+		t.Fatalf("failed to parse corpus: %v", err)
+	}
+
+	for _, tt := range corpus {
+		query := tt.PURL
+		if query == "" {
+			query = tt.Type + " " + tt.Name
+		}
+		t.Run(tt.Note+"/"+query, func(t *testing.T) {
+			manager, err := NewEOLDatabaseManager(filepath.Join(t.TempDir(), "test.db"))
+			if err != nil {
+				t.Fatalf("NewEOLDatabaseManager() error = %v", err)
+			}
+			defer manager.Close()
+
+			productID, _ := manager.UpsertProduct(ProductData{Name: "product", Category: "framework"})
+			if _, err := manager.UpsertIdentifiers(productID, []Identifier{{Type: "purl", ID: tt.Identifier}}); err != nil {
+				t.Fatalf("UpsertIdentifiers() error = %v", err)
+			}
+
+			var found *Product
+			if tt.PURL != "" {
+				found, _, _, err = manager.LookupByPURL(tt.PURL)
+			} else {
+				found, _, err = manager.LookupByPURLPrefix(tt.Type, tt.Name)
+			}
+			if err != nil {
+				t.Fatalf("lookup error = %v", err)
+			}
+			if (found != nil) != tt.Match {
+				t.Errorf("identifier %s matched = %v, want %v", tt.Identifier, found != nil, tt.Match)
+			}
+		})
+	}
+}
+
+// TestLookupByPURLDeterministic tests that a PURL covered by several products always
+// resolves to the same one
+func TestLookupByPURLDeterministic(t *testing.T) {
+	manager, err := NewEOLDatabaseManager(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewEOLDatabaseManager() error = %v", err)
+	}
+	defer manager.Close()
+
+	for _, name := range []string{"zulu", "azul-zulu"} {
+		productID, _ := manager.UpsertProduct(ProductData{Name: name, Category: "lang"})
+		manager.UpsertIdentifiers(productID, []Identifier{{Type: "purl", ID: "pkg:generic/zulu"}})
+	}
+
+	found, _, _, err := manager.LookupByPURL("pkg:generic/zulu@21.30.15")
+	if err != nil {
+		t.Fatalf("LookupByPURL() error = %v", err)
+	}
+	if found == nil || found.Name != "azul-zulu" {
+		t.Errorf("LookupByPURL() = %v, want azul-zulu", found)
+	}
+
+	if _, _, _, err := manager.LookupByPURL("generic/zulu"); err == nil {
+		t.Error("LookupByPURL() should reject a malformed PURL")
+	}
+}
+
+// TestMigrateCanonicalIdentifiers tests that databases created before canonical PURL
+// matching are migrated
+func TestMigrateCanonicalIdentifiers(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	manager, err := NewEOLDatabaseManager(dbPath)
+	if err != nil {
+		t.Fatalf("NewEOLDatabaseManager() error = %v", err)
+	}
+
+	// Recreate the identifiers table as it was, holding a PURL without a canonical form
+	productID, _ := manager.UpsertProduct(ProductData{Name: "django", Category: "framework"})
+	for _, query := range []string{
+		`DROP TABLE identifiers`,
+		`CREATE TABLE identifiers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			product_id INTEGER NOT NULL,
+			identifier_type TEXT NOT NULL,
+			identifier_value TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(product_id, identifier_type, identifier_value)
+		)`,
+	} {
+		if _, err := manager.db.Exec(query); err != nil {
+			t.Fatalf("failed to recreate identifiers table: %v", err)
+		}
+	}
+	if _, err := manager.db.Exec(`INSERT INTO identifiers (product_id, identifier_type, identifier_value) VALUES (?, 'purl', 'pkg:pypi/Django')`, productID); err != nil {
+		t.Fatalf("failed to insert identifier: %v", err)
+	}
+	manager.Close()
+
+	manager, err = NewEOLDatabaseManager(dbPath)
+	if err != nil {
+		t.Fatalf("NewEOLDatabaseManager() on an old database error = %v", err)
+	}
+	defer manager.Close()
+
+	found, _, _, err := manager.LookupByPURL("pkg:pypi/django@4.2.1")
+	if err != nil {
+		t.Fatalf("LookupByPURL() error = %v", err)
+	}
+	if found == nil || found.Name != "django" {
+		t.Errorf("LookupByPURL() = %v, want django after migration", found)
+	}
+}
+
+// TestMigrateStaleCanonicalIdentifiers tests that keys stored lowercased by an earlier
+// version are refreshed for case-sensitive PURL types
+func TestMigrateStaleCanonicalIdentifiers(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	manager, err := NewEOLDatabaseManager(dbPath)
+	if err != nil {
+		t.Fatalf("NewEOLDatabaseManager() error = %v", err)
+	}
+	productID, _ := manager.UpsertProduct(ProductData{Name: "dotnet", Category: "framework"})
+	if _, err := manager.UpsertIdentifiers(productID, []Identifier{{Type: "purl", ID: "pkg:nuget/Microsoft.AspNetCore.App"}}); err != nil {
+		t.Fatalf("UpsertIdentifiers() error = %v", err)
+	}
+	if _, err := manager.db.Exec(`UPDATE identifiers SET canonical_value = 'pkg:nuget/microsoft.aspnetcore.app'`); err != nil {
+		t.Fatalf("failed to store a stale key: %v", err)
+	}
+	manager.Close()
+
+	manager, err = NewEOLDatabaseManager(dbPath)
+	if err != nil {
+		t.Fatalf("NewEOLDatabaseManager() on an old database error = %v", err)
+	}
+	defer manager.Close()
+
+	found, _, _, err := manager.LookupByPURL("pkg:nuget/Microsoft.AspNetCore.App@8.0.1")
+	if err != nil {
+		t.Fatalf("LookupByPURL() error = %v", err)
+	}
+	if found == nil || found.Name != "dotnet" {
+		t.Errorf("LookupByPURL() = %v, want dotnet after migration", found)
+	}
+}
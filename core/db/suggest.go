@@ -0,0 +1,305 @@
+package db
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Suggestion is a product whose name, alias or identifier resembles a package that no
+// lookup matched
+type Suggestion struct {
+	Product string
+	Score   float64 // Resemblance from 0 to 1
+	Matched string  // Name, alias or identifier that resembled the package
+}
+
+// suggestMinScore is the resemblance below which products are not suggested
+const suggestMinScore = 0.5
+
+// suggestVendorBonus is added to the score of products with the same vendor as the
+// package, in proportion to how many vendor tokens they share
+const suggestVendorBonus = 0.1
+
+// suggestStopTokens are name tokens too common in package names to tell products apart
+var suggestStopTokens = map[string]bool{
+	"lib":    true,
+	"dev":    true,
+	"dbg":    true,
+	"doc":    true,
+	"docs":   true,
+	"common": true,
+	"bin":    true,
+	"data":   true,
+	"utils":  true,
+	"tools":  true,
+	"org":    true,
+	"com":    true,
+	"io":     true,
+	"net":    true,
+}
+
+// suggestTerm is a name, alias or identifier of the product at a position of the index
+type suggestTerm struct {
+	product int
+	text    string
+	compact string
+	counts  charCounts
+	tokens  []string
+}
+
+// charCounts counts the letters and digits of a compact name, with other characters
+// counted together. Names sharing few characters cannot be a small edit apart.
+type charCounts [37]uint8
+
+// loadSuggestTerms collects the names, labels, aliases and identifier names of every
+// product, and the vendor tokens of their CPEs and PURL namespaces
+func (x *Index) loadSuggestTerms() {
+	x.vendors = make([][]string, len(x.products))
+	for i, p := range x.products {
+		x.addSuggestTerm(i, p.Name, p.Name)
+		if p.Label.Valid {
+			x.addSuggestTerm(i, p.Label.String, p.Label.String)
+		}
+		var aliases []string
+		if p.Aliases.Valid && json.Unmarshal([]byte(p.Aliases.String), &aliases) == nil {
+			for _, alias := range aliases {
+				x.addSuggestTerm(i, alias, alias)
+			}
+		}
+
+		vendors := nameTokens(p.Name)
+		for _, id := range x.identifiers[p.Name] {
+			switch id.Type {
+			case "purl":
+				if parsed, err := ParsePURL(id.Value); err == nil {
+					x.addSuggestTerm(i, id.Value, parsed.Name)
+					vendors = append(vendors, nameTokens(parsed.Namespace)...)
+				}
+			case "cpe":
+				if vendor, product, ok := cpeVendorProduct(id.Value); ok {
+					x.addSuggestTerm(i, id.Value, product)
+					vendors = append(vendors, nameTokens(vendor)...)
+				}
+			case "repology":
+				x.addSuggestTerm(i, id.Value, id.Value)
+			}
+		}
+		x.vendors[i] = vendors
+	}
+}
+
+// addSuggestTerm records a name a product may be suggested by
+func (x *Index) addSuggestTerm(product int, text, name string) {
+	compact := compactName(name)
+	if compact == "" {
+		return
+	}
+	x.terms = append(x.terms, suggestTerm{
+		product: product,
+		text:    text,
+		compact: compact,
+		counts:  countChars(compact),
+		tokens:  nameTokens(name),
+	})
+}
+
+// Suggest returns up to limit products resembling a package, best first. Each name,
+// alias and identifier is compared with the package name by edit distance and by
+// overlap of their name tokens; products sharing a vendor with the package (from its
+// CPE, PURL namespace or distributor) rank higher.
+func (x *Index) Suggest(name, vendor string, limit int) []Suggestion {
+	compact := compactName(name)
+	if compact == "" || limit <= 0 {
+		return nil
+	}
+	tokens := nameTokens(name)
+	vendorTokens := nameTokens(vendor)
+
+	counts := countChars(compact)
+	var rows [2][]int
+	best := make(map[int]Suggestion)
+	for _, term := range x.terms {
+		bonus := suggestVendorBonus * tokenOverlap(vendorTokens, x.vendors[term.product])
+
+		// The edit distance only matters when it beats the token overlap, the threshold
+		// and the product's best term so far
+		score := tokenOverlap(tokens, term.tokens)
+		floor := max(score, suggestMinScore-bonus, best[term.product].Score-bonus)
+		if similarity, ok := editSimilarity(compact, term.compact, counts, term.counts, floor, &rows); ok {
+			score = max(score, similarity)
+		}
+		score = min(1, score+bonus)
+
+		if score < suggestMinScore || score <= best[term.product].Score {
+			continue
+		}
+		best[term.product] = Suggestion{Product: x.products[term.product].Name, Score: score, Matched: term.text}
+	}
+
+	suggestions := make([]Suggestion, 0, len(best))
+	for _, s := range best {
+		suggestions = append(suggestions, s)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Product < suggestions[j].Product
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// cpeVendorProduct returns the vendor and product fields of a CPE 2.2 or 2.3 string
+func cpeVendorProduct(cpe string) (string, string, bool) {
+	var fields []string
+	if rest, ok := strings.CutPrefix(strings.ToLower(cpe), "cpe:2.3:"); ok {
+		fields = strings.Split(rest, ":")
+	} else if rest, ok := strings.CutPrefix(strings.ToLower(cpe), "cpe:/"); ok {
+		fields = strings.Split(rest, ":")
+	}
+	if len(fields) < 3 || fields[2] == "" || fields[2] == "*" {
+		return "", "", false
+	}
+	return fields[1], fields[2], true
+}
+
+// compactName lowercases a name and drops everything but letters and digits, so that
+// "Node.js", "node-js" and "nodejs" compare equal
+func compactName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// nameTokens splits a name into lowercase words, dropping numbers, common words such as
+// "lib" or "dev", and the version digits ending a word ("python3" is "python")
+func nameTokens(name string) []string {
+	var tokens []string
+	for _, token := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if trimmed := strings.TrimRightFunc(token, unicode.IsDigit); len(trimmed) >= 3 {
+			token = trimmed
+		}
+		if suggestStopTokens[token] || strings.TrimFunc(token, unicode.IsDigit) == "" {
+			continue
+		}
+		if !slices.Contains(tokens, token) {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// tokenOverlap is the Dice coefficient of two token sets: twice the shared tokens over
+// the tokens of both
+func tokenOverlap(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for _, token := range a {
+		if slices.Contains(b, token) {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+// editSimilarity is one minus the edit distance of two names relative to the longer.
+// It reports false without comparing the names in full when the similarity cannot
+// exceed floor.
+func editSimilarity(a, b string, aCounts, bCounts charCounts, floor float64, rows *[2][]int) (float64, bool) {
+	longer := max(len(a), len(b))
+	if longer == 0 {
+		return 0, false
+	}
+	limit := int(float64(longer) * (1 - floor))
+
+	// Every character of the longer name that the other lacks takes an edit
+	shared := 0
+	for i := range aCounts {
+		shared += int(min(aCounts[i], bCounts[i]))
+	}
+	if longer-shared > limit {
+		return 0, false
+	}
+
+	distance, ok := boundedLevenshtein(a, b, limit, rows)
+	if !ok {
+		return 0, false
+	}
+	return 1 - float64(distance)/float64(longer), true
+}
+
+// countChars counts the characters of a compact name
+func countChars(compact string) charCounts {
+	var counts charCounts
+	for i := 0; i < len(compact); i++ {
+		c := compact[i]
+		bucket := 36
+		switch {
+		case c >= 'a' && c <= 'z':
+			bucket = int(c - 'a')
+		case c >= '0' && c <= '9':
+			bucket = 26 + int(c-'0')
+		}
+		if counts[bucket] < 255 {
+			counts[bucket]++
+		}
+	}
+	return counts
+}
+
+// boundedLevenshtein returns the number of single-byte insertions, deletions and
+// substitutions turning a into b, or false once it exceeds limit. rows holds the
+// buffers reused across calls.
+func boundedLevenshtein(a, b string, limit int, rows *[2][]int) (int, bool) {
+	if abs(len(a)-len(b)) > limit {
+		return 0, false
+	}
+	for i := range rows {
+		if cap(rows[i]) < len(b)+1 {
+			rows[i] = make([]int, len(b)+1)
+		}
+		rows[i] = rows[i][:len(b)+1]
+	}
+	prev, curr := rows[0], rows[1]
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return 0, false
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)], prev[len(b)] <= limit
+}
+
+// abs returns the absolute value of an int
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
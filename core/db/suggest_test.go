@@ -0,0 +1,130 @@
+package db
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// newSuggestTestIndex loads an index of products with labels, aliases and identifiers
+func newSuggestTestIndex(t *testing.T) *Index {
+	t.Helper()
+	manager, err := NewEOLDatabaseManager(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewEOLDatabaseManager() error = %v", err)
+	}
+	t.Cleanup(func() { manager.Close() })
+
+	products := []ProductData{
+		{Name: "nodejs", Label: "Node.js", Category: "lang", Identifiers: []Identifier{{Type: "purl", ID: "pkg:generic/node"}}},
+		{Name: "postgresql", Label: "PostgreSQL", Category: "database", Identifiers: []Identifier{{Type: "cpe", ID: "cpe:2.3:a:postgresql:postgresql"}}},
+		{Name: "spring-boot", Label: "Spring Boot", Category: "framework", Identifiers: []Identifier{
+			{Type: "purl", ID: "pkg:maven/org.springframework.boot/spring-boot"},
+		}},
+		{Name: "spring-framework", Label: "Spring Framework", Category: "framework", Identifiers: []Identifier{
+			{Type: "purl", ID: "pkg:maven/org.springframework/spring-core"},
+		}},
+		{Name: "python", Label: "Python", Category: "lang", Aliases: []string{"cpython"}},
+		{Name: "mariadb", Label: "MariaDB", Category: "database"},
+	}
+	for _, p := range products {
+		productID, err := manager.UpsertProduct(p)
+		if err != nil {
+			t.Fatalf("UpsertProduct(%s) error = %v", p.Name, err)
+		}
+		if _, err := manager.UpsertIdentifiers(productID, p.Identifiers); err != nil {
+			t.Fatalf("UpsertIdentifiers(%s) error = %v", p.Name, err)
+		}
+	}
+
+	index, err := manager.LoadIndex()
+	if err != nil {
+		t.Fatalf("LoadIndex() error = %v", err)
+	}
+	return index
+}
+
+// TestSuggest tests the fuzzy search of products resembling unmatched packages
+func TestSuggest(t *testing.T) {
+	index := newSuggestTestIndex(t)
+
+	tests := []struct {
+		name    string
+		pkgName string
+		vendor  string
+		want    []string
+		matched string
+	}{
+		{"label punctuation", "node-js", "", []string{"nodejs"}, "nodejs"},
+		{"edit distance", "postgres", "", []string{"postgresql"}, "postgresql"},
+		{"token overlap", "acme-postgresql-client", "", []string{"postgresql"}, "postgresql"},
+		{"alias", "cpython3", "", []string{"python"}, "cpython"},
+		{"vendor", "spring", "org.springframework.boot", []string{"spring-boot", "spring-framework"}, "spring-boot"},
+		{"nothing alike", "zlib1g", "", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions := index.Suggest(tt.pkgName, tt.vendor, 3)
+			var got []string
+			for _, s := range suggestions {
+				got = append(got, s.Product)
+				if s.Score < suggestMinScore || s.Score > 1 {
+					t.Errorf("%s scored %.2f, want between %.1f and 1", s.Product, s.Score, suggestMinScore)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Suggest(%q, %q) = %v, want %v", tt.pkgName, tt.vendor, got, tt.want)
+			}
+			if len(suggestions) > 0 && suggestions[0].Matched != tt.matched {
+				t.Errorf("Suggest(%q) matched %q, want %q", tt.pkgName, suggestions[0].Matched, tt.matched)
+			}
+		})
+	}
+
+	if got := index.Suggest("spring", "", 1); len(got) != 1 {
+		t.Errorf("Suggest() with limit 1 returned %d suggestions", len(got))
+	}
+}
+
+// TestNameTokens tests splitting package names into comparable words
+func TestNameTokens(t *testing.T) {
+	tests := map[string][]string{
+		"python3.11":              {"python"},
+		"libssl-dev":              {"libssl"},
+		"spring-boot-starter-web": {"spring", "boot", "starter", "web"},
+		"log4j-core":              {"log4j", "core"},
+		"org.springframework":     {"springframework"},
+		"lib32-common":            nil,
+	}
+
+	for name, want := range tests {
+		if got := nameTokens(name); !slices.Equal(got, want) {
+			t.Errorf("nameTokens(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+// TestEditSimilarity tests the bounded edit distance against its cut-offs
+func TestEditSimilarity(t *testing.T) {
+	var rows [2][]int
+	tests := []struct {
+		a, b  string
+		floor float64
+		want  float64
+		ok    bool
+	}{
+		{"postgres", "postgresql", 0.5, 0.8, true},
+		{"nodejs", "nodejs", 0.5, 1, true},
+		{"kitten", "sitting", 0.4, 1 - 3.0/7, true},
+		{"kitten", "sitting", 0.7, 0, false},
+		{"redis", "mariadb", 0.5, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := editSimilarity(tt.a, tt.b, countChars(tt.a), countChars(tt.b), tt.floor, &rows)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("editSimilarity(%q, %q, %.1f) = %.3f, %v, want %.3f, %v", tt.a, tt.b, tt.floor, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/anchore/stereoscope/pkg/image"
 	"github.com/anchore/syft/syft"
+	"github.com/anchore/syft/syft/cataloging/filecataloging"
 	"github.com/anchore/syft/syft/format"
 	"github.com/anchore/syft/syft/format/cyclonedxjson"
 	"github.com/anchore/syft/syft/format/spdxjson"
@@ -46,6 +47,7 @@ type Generator struct {
 	defaultFormat    OutputFormat
 	credentials      []RegistryCredentials
 	caFileOrDir      string
+	contentGlobs     []string
 	progressCallback ProgressCallback
 }
 
@@ -98,6 +100,13 @@ func (g *Generator) WithCAFileOrDir(path string) *Generator {
 	return g
 }
 
+// WithFileContents records the contents of files matching the globs in the SBOM
+// (base64 encoded in Artifacts.FileContents)
+func (g *Generator) WithFileContents(globs ...string) *Generator {
+	g.contentGlobs = append(g.contentGlobs, globs...)
+	return g
+}
+
 // WithProgress sets a callback for progress updates
 func (g *Generator) WithProgress(callback ProgressCallback) *Generator {
 	g.progressCallback = callback
@@ -145,7 +154,7 @@ func (g *Generator) generate(ctx context.Context, reference string) (*sbom.SBOM,
 
 	g.progress("catalog", "Cataloging packages...")
 
-	result, err := syft.CreateSBOM(ctx, src, nil)
+	result, err := syft.CreateSBOM(ctx, src, g.buildSBOMConfig())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SBOM: %w", err)
 	}
@@ -155,6 +164,17 @@ func (g *Generator) generate(ctx context.Context, reference string) (*sbom.SBOM,
 	return result, nil
 }
 
+// buildSBOMConfig creates the cataloging configuration, or nil for syft's defaults
+func (g *Generator) buildSBOMConfig() *syft.CreateSBOMConfig {
+	if len(g.contentGlobs) == 0 {
+		return nil
+	}
+
+	files := filecataloging.DefaultConfig()
+	files.Content.Globs = g.contentGlobs
+	return syft.DefaultCreateSBOMConfig().WithFilesConfig(files)
+}
+
 // buildSourceConfig creates the source configuration with authentication
 func (g *Generator) buildSourceConfig() *syft.GetSourceConfig {
 	cfg := syft.DefaultGetSourceConfig()
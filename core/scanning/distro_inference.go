@@ -0,0 +1,313 @@
+package scanning
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/linux"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/anchore/syft/syft/source"
+)
+
+// distroEvidenceFiles are files whose contents identify the distro of images without
+// an os-release, such as Google distroless (which keeps /etc/debian_version) and
+// Alpine-based images stripped of /etc/os-release
+var distroEvidenceFiles = []string{"/etc/debian_version", "/etc/apk/repositories"}
+
+// baseImageAnnotation is the OCI annotation naming the image an image was built from
+const baseImageAnnotation = "org.opencontainers.image.base.name"
+
+// inferredDistro is a distro release inferred from indirect evidence
+type inferredDistro struct {
+	release    linux.Release
+	evidence   string
+	confidence MatchConfidence
+}
+
+var (
+	// apkRepository matches Alpine repository URLs (.../alpine/v3.18/main, .../alpine/edge/community)
+	apkRepository = regexp.MustCompile(`/alpine/(v\d+\.\d+|edge)/`)
+	// distrolessImage matches distroless image names such as static-debian12 or base-nossl-debian11
+	distrolessImage = regexp.MustCompile(`-(debian|ubuntu)(\d+(?:\.\d+)?)$`)
+	// ubiImage matches Red Hat Universal Base Image names (ubi9, ubi8-minimal, ubi8/ubi-minimal)
+	ubiImage = regexp.MustCompile(`(?:^|/)ubi(\d+)(?:[-/]|$)`)
+	// imageTagVersion matches the release at the start of an image tag (12-slim, 3.18.4, 22.04)
+	imageTagVersion = regexp.MustCompile(`^\d+(?:\.\d+)*`)
+	// debianSecuritySuffix matches Debian stable and security update versions (1.2+deb12u1, 2.36-9+deb12u4)
+	debianSecuritySuffix = regexp.MustCompile(`[+~]deb(\d+)u\d+`)
+	// ubuntuUpdateSuffix matches Ubuntu update versions that name the release (1.2-0ubuntu0.22.04.1)
+	ubuntuUpdateSuffix = regexp.MustCompile(`ubuntu0\.(\d{2}\.\d{2})`)
+	// rpmDistTag matches the dist tag of RPM releases (25.el9, 1.el8_6, 3.amzn2, 1.fc39)
+	rpmDistTag = regexp.MustCompile(`\.(el|amzn|fc)(\d+)`)
+)
+
+// distroImages maps official image repositories to their os-release ID
+var distroImages = map[string]string{
+	"debian":      "debian",
+	"ubuntu":      "ubuntu",
+	"alpine":      "alpine",
+	"fedora":      "fedora",
+	"centos":      "centos",
+	"rockylinux":  "rocky",
+	"almalinux":   "almalinux",
+	"amazonlinux": "amzn",
+	"oraclelinux": "ol",
+	"archlinux":   "arch",
+	"photon":      "photon",
+}
+
+// rpmDistTags maps RPM dist tags to the os-release ID of the distro they belong to.
+// el is shared by RHEL and its rebuilds, which follow the same lifecycle.
+var rpmDistTags = map[string]string{
+	"el":   "rhel",
+	"amzn": "amzn",
+	"fc":   "fedora",
+}
+
+// inferDistro infers the distro of an image without an os-release, from OCI base image
+// annotations, distro files and the versions of installed packages, in that order
+func inferDistro(s *sbom.SBOM) *inferredDistro {
+	if inferred := distroFromAnnotations(s.Source); inferred != nil {
+		return inferred
+	}
+	if inferred := distroFromFiles(s.Artifacts.FileContents); inferred != nil {
+		return inferred
+	}
+	return distroFromPackages(s.Artifacts.Packages.Sorted())
+}
+
+// checkInferredOS evaluates an inferred distro like an os-release. The match records
+// the evidence and the lower confidence of the inference.
+func (s *Scanner) checkInferredOS(inferred *inferredDistro) *OSInfo {
+	if inferred == nil {
+		return nil
+	}
+
+	release := inferred.release
+	if release.Name == "" {
+		release.Name = release.ID
+	}
+	version := release.VersionID
+	if version == "" {
+		version = release.VersionCodename
+	}
+	release.PrettyName = strings.TrimSpace(release.Name+" "+version) + " (inferred)"
+
+	osInfo := s.checkOSEOL(&release)
+	osInfo.Inferred = true
+	if osInfo.Match != nil {
+		osInfo.Match.Method = MatchInferredOS
+		osInfo.Match.Identifier = inferred.evidence
+		osInfo.Match.Confidence = inferred.confidence
+	}
+	return osInfo
+}
+
+// distroFromAnnotations infers the distro from the base image named in the image's OCI
+// annotations or labels
+func distroFromAnnotations(description source.Description) *inferredDistro {
+	var metadata source.ImageMetadata
+	switch m := description.Metadata.(type) {
+	case source.ImageMetadata:
+		metadata = m
+	case *source.ImageMetadata:
+		metadata = *m
+	default:
+		return nil
+	}
+
+	base := metadata.Labels[baseImageAnnotation]
+	if base == "" {
+		var manifest struct {
+			Annotations map[string]string `json:"annotations"`
+		}
+		if err := json.Unmarshal(metadata.RawManifest, &manifest); err == nil {
+			base = manifest.Annotations[baseImageAnnotation]
+		}
+	}
+	if base == "" {
+		return nil
+	}
+
+	release, ok := distroFromImageName(base)
+	if !ok {
+		return nil
+	}
+	return &inferredDistro{release: release, evidence: baseImageAnnotation + "=" + base, confidence: ConfidenceMedium}
+}
+
+// distroFromImageName returns the distro release of a base image reference such as
+// debian:12-slim, gcr.io/distroless/static-debian12 or registry.access.redhat.com/ubi9
+func distroFromImageName(ref string) (linux.Release, bool) {
+	ref, _, _ = strings.Cut(ref, "@")
+	repo, tag := ref, ""
+	if idx := strings.LastIndexByte(ref, ':'); idx > strings.LastIndexByte(ref, '/') {
+		repo, tag = ref[:idx], ref[idx+1:]
+	}
+	repo = strings.ToLower(repo)
+	name := repo[strings.LastIndexByte(repo, '/')+1:]
+
+	switch {
+	case strings.HasPrefix(repo, "cgr.dev/chainguard/") || strings.Contains(name, "wolfi"):
+		return linux.Release{ID: "wolfi", Name: "Wolfi"}, true
+	case distrolessImage.MatchString(name):
+		match := distrolessImage.FindStringSubmatch(name)
+		return linux.Release{ID: match[1], VersionID: match[2]}, true
+	case ubiImage.MatchString(repo):
+		return linux.Release{ID: "rhel", VersionID: ubiImage.FindStringSubmatch(repo)[1]}, true
+	}
+
+	id, ok := distroImages[name]
+	if !ok {
+		return linux.Release{}, false
+	}
+	release := linux.Release{ID: id, VersionID: imageTagVersion.FindString(tag)}
+	if words := releaseWords(tag); release.VersionID == "" && len(words) > 0 && words[0] != "latest" {
+		// Codename tags such as bookworm-slim or jammy
+		release.VersionCodename = words[0]
+	}
+	return release, true
+}
+
+// distroFromFiles infers the distro from /etc/debian_version or the Alpine repositories
+func distroFromFiles(contents map[file.Coordinates]string) *inferredDistro {
+	if version := fileContent(contents, "/etc/debian_version"); version != "" {
+		release := linux.Release{ID: "debian", VersionID: version}
+		if words := releaseWords(version); !imageTagVersion.MatchString(version) && len(words) > 0 {
+			// Testing and unstable report a codename ("trixie/sid")
+			release = linux.Release{ID: "debian", Version: version, VersionCodename: words[0]}
+		}
+		return &inferredDistro{release: release, evidence: "/etc/debian_version=" + version, confidence: ConfidenceMedium}
+	}
+
+	repositories := fileContent(contents, "/etc/apk/repositories")
+	if strings.Contains(repositories, "packages.wolfi.dev") {
+		return &inferredDistro{release: linux.Release{ID: "wolfi", Name: "Wolfi"}, evidence: "/etc/apk/repositories", confidence: ConfidenceMedium}
+	}
+	if match := apkRepository.FindStringSubmatch(repositories); match != nil {
+		version := strings.TrimPrefix(match[1], "v")
+		return &inferredDistro{release: linux.Release{ID: "alpine", VersionID: version}, evidence: "/etc/apk/repositories " + match[1], confidence: ConfidenceMedium}
+	}
+	return nil
+}
+
+// fileContent returns the decoded, trimmed contents of a file recorded in the SBOM
+func fileContent(contents map[file.Coordinates]string, path string) string {
+	for coordinates, encoded := range contents {
+		if coordinates.RealPath != path {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(decoded))
+	}
+	return ""
+}
+
+// distroRelease identifies a distro release inferred from package versions
+type distroRelease struct {
+	id, version string
+}
+
+// distroFromPackages infers the distro from the installed packages: the Alpine or Wolfi
+// release packages, and otherwise the release most package versions point at
+// (+deb12u1, 0ubuntu0.22.04.1, .el8, .amzn2). Debian packages recorded in distroless
+// status.d files identify Debian even when no version names the release.
+func distroFromPackages(packages []pkg.Package) *inferredDistro {
+	votes := make(map[distroRelease]int)
+	evidence := make(map[distroRelease]string)
+	vote := func(release distroRelease, p pkg.Package) {
+		votes[release]++
+		if _, ok := evidence[release]; !ok {
+			evidence[release] = p.Name + "@" + p.Version
+		}
+	}
+	statusDir := ""
+
+	for _, p := range packages {
+		switch p.Type {
+		case pkg.ApkPkg:
+			switch p.Name {
+			case "alpine-release", "alpine-baselayout-data":
+				version := p.Version
+				if idx := strings.LastIndex(version, "-r"); idx >= 0 {
+					version = version[:idx]
+				}
+				return &inferredDistro{release: linux.Release{ID: "alpine", VersionID: version}, evidence: "package " + p.Name + "@" + p.Version, confidence: ConfidenceMedium}
+			case "wolfi-baselayout":
+				return &inferredDistro{release: linux.Release{ID: "wolfi", Name: "Wolfi"}, evidence: "package " + p.Name + "@" + p.Version, confidence: ConfidenceMedium}
+			}
+		case pkg.DebPkg:
+			if statusDir == "" && inDpkgStatusDir(p) {
+				statusDir = p.Name + "@" + p.Version
+			}
+		}
+		if release, ok := packageRelease(p); ok {
+			vote(release, p)
+		}
+	}
+	if len(votes) == 0 {
+		if statusDir != "" {
+			return &inferredDistro{release: linux.Release{ID: "debian"}, evidence: "/var/lib/dpkg/status.d, e.g. " + statusDir, confidence: ConfidenceLow}
+		}
+		return nil
+	}
+
+	// The release with the most votes wins; ties go to the newest release
+	releases := make([]distroRelease, 0, len(votes))
+	for release := range votes {
+		releases = append(releases, release)
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		if votes[releases[i]] != votes[releases[j]] {
+			return votes[releases[i]] > votes[releases[j]]
+		}
+		return compareCycleNames(releases[i].version, releases[j].version) > 0
+	})
+
+	release := releases[0]
+	return &inferredDistro{
+		release:    linux.Release{ID: release.id, VersionID: release.version},
+		evidence:   fmt.Sprintf("%d package version(s), e.g. %s", votes[release], evidence[release]),
+		confidence: ConfidenceLow,
+	}
+}
+
+// packageRelease returns the distro release a package version names, if any: Debian
+// stable and security updates (+deb12u1), Ubuntu updates (0ubuntu0.22.04.1) and RPM
+// dist tags (.el8, .amzn2, .fc39)
+func packageRelease(p pkg.Package) (distroRelease, bool) {
+	switch p.Type {
+	case pkg.DebPkg:
+		if match := debianSecuritySuffix.FindStringSubmatch(p.Version); match != nil {
+			return distroRelease{"debian", match[1]}, true
+		}
+		if match := ubuntuUpdateSuffix.FindStringSubmatch(p.Version); match != nil {
+			return distroRelease{"ubuntu", match[1]}, true
+		}
+	case pkg.RpmPkg:
+		if match := rpmDistTag.FindStringSubmatch(p.Version); match != nil {
+			return distroRelease{rpmDistTags[match[1]], match[2]}, true
+		}
+	}
+	return distroRelease{}, false
+}
+
+// inDpkgStatusDir reports whether a Debian package was recorded in a distroless
+// /var/lib/dpkg/status.d file rather than the dpkg status database
+func inDpkgStatusDir(p pkg.Package) bool {
+	for _, location := range p.Locations.ToSlice() {
+		if strings.HasPrefix(location.RealPath, "/var/lib/dpkg/status.d/") {
+			return true
+		}
+	}
+	return false
+}
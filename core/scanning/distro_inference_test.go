@@ -0,0 +1,168 @@
+package scanning
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/linux"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+// TestDistroFromImageName tests reading the distro release from base image references
+func TestDistroFromImageName(t *testing.T) {
+	tests := []struct {
+		ref      string
+		id       string
+		version  string
+		codename string
+		ok       bool
+	}{
+		{"debian:12-slim", "debian", "12", "", true},
+		{"docker.io/library/alpine:3.18.4", "alpine", "3.18.4", "", true},
+		{"ubuntu:jammy-20240111", "ubuntu", "", "jammy", true},
+		{"debian:bookworm@sha256:abc", "debian", "", "bookworm", true},
+		{"gcr.io/distroless/static-debian12:nonroot", "debian", "12", "", true},
+		{"gcr.io/distroless/base-nossl-debian11", "debian", "11", "", true},
+		{"registry.access.redhat.com/ubi9/ubi-minimal:9.3", "rhel", "9", "", true},
+		{"registry.access.redhat.com/ubi8:latest", "rhel", "8", "", true},
+		{"cgr.dev/chainguard/static:latest", "wolfi", "", "", true},
+		{"localhost:5000/debian:latest", "debian", "", "", true},
+		{"myorg/app:1.2.3", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			release, ok := distroFromImageName(tt.ref)
+			if ok != tt.ok || release.ID != tt.id || release.VersionID != tt.version || release.VersionCodename != tt.codename {
+				t.Errorf("distroFromImageName() = %q %q %q (%v), want %q %q %q (%v)",
+					release.ID, release.VersionID, release.VersionCodename, ok, tt.id, tt.version, tt.codename, tt.ok)
+			}
+		})
+	}
+}
+
+// TestDistroFromAnnotations tests reading the base image from labels and manifest annotations
+func TestDistroFromAnnotations(t *testing.T) {
+	labels := source.Description{Metadata: source.ImageMetadata{
+		Labels: map[string]string{baseImageAnnotation: "debian:12"},
+	}}
+	if inferred := distroFromAnnotations(labels); inferred == nil || inferred.release.ID != "debian" || inferred.confidence != ConfidenceMedium {
+		t.Errorf("distroFromAnnotations(labels) = %+v", inferred)
+	}
+
+	manifest := source.Description{Metadata: source.ImageMetadata{
+		RawManifest: []byte(`{"annotations": {"org.opencontainers.image.base.name": "alpine:3.19"}}`),
+	}}
+	if inferred := distroFromAnnotations(manifest); inferred == nil || inferred.release.VersionID != "3.19" {
+		t.Errorf("distroFromAnnotations(manifest) = %+v", inferred)
+	}
+
+	if inferred := distroFromAnnotations(source.Description{Metadata: source.ImageMetadata{}}); inferred != nil {
+		t.Errorf("distroFromAnnotations() without annotations = %+v, want nil", inferred)
+	}
+}
+
+// TestDistroFromFiles tests reading the distro from /etc/debian_version and apk repositories
+func TestDistroFromFiles(t *testing.T) {
+	contents := func(path, content string) map[file.Coordinates]string {
+		return map[file.Coordinates]string{
+			file.NewCoordinates(path, "sha256:layer"): base64.StdEncoding.EncodeToString([]byte(content)),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		contents map[file.Coordinates]string
+		id       string
+		version  string
+		codename string
+	}{
+		{"debian version", contents("/etc/debian_version", "12.4\n"), "debian", "12.4", ""},
+		{"debian testing", contents("/etc/debian_version", "trixie/sid\n"), "debian", "", "trixie"},
+		{"alpine repositories", contents("/etc/apk/repositories", "https://dl-cdn.alpinelinux.org/alpine/v3.18/main\nhttps://dl-cdn.alpinelinux.org/alpine/v3.18/community\n"), "alpine", "3.18", ""},
+		{"alpine edge", contents("/etc/apk/repositories", "https://dl-cdn.alpinelinux.org/alpine/edge/main\n"), "alpine", "edge", ""},
+		{"wolfi", contents("/etc/apk/repositories", "https://packages.wolfi.dev/os\n"), "wolfi", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inferred := distroFromFiles(tt.contents)
+			if inferred == nil {
+				t.Fatal("distroFromFiles() = nil")
+			}
+			r := inferred.release
+			if r.ID != tt.id || r.VersionID != tt.version || r.VersionCodename != tt.codename {
+				t.Errorf("distroFromFiles() = %q %q %q, want %q %q %q", r.ID, r.VersionID, r.VersionCodename, tt.id, tt.version, tt.codename)
+			}
+		})
+	}
+
+	if inferred := distroFromFiles(nil); inferred != nil {
+		t.Errorf("distroFromFiles(nil) = %+v, want nil", inferred)
+	}
+}
+
+// TestDistroFromPackages tests inferring the distro from installed package versions
+func TestDistroFromPackages(t *testing.T) {
+	deb := func(name, version string) pkg.Package {
+		return pkg.Package{Name: name, Version: version, Type: pkg.DebPkg,
+			Locations: file.NewLocationSet(file.NewLocation("/var/lib/dpkg/status.d/" + name))}
+	}
+	rpm := func(name, version string) pkg.Package {
+		return pkg.Package{Name: name, Version: version, Type: pkg.RpmPkg}
+	}
+
+	tests := []struct {
+		name     string
+		packages []pkg.Package
+		id       string
+		version  string
+	}{
+		{"debian security suffix", []pkg.Package{deb("libc6", "2.36-9+deb12u4"), deb("libssl3", "3.0.11-1~deb12u2"), deb("tzdata", "2024a-0+deb11u1")}, "debian", "12"},
+		{"distroless without suffix", []pkg.Package{deb("base-files", "12.4"), deb("netbase", "6.4")}, "debian", ""},
+		{"ubuntu update", []pkg.Package{{Name: "libssl3", Version: "3.0.2-0ubuntu1.15", Type: pkg.DebPkg}, {Name: "tzdata", Version: "2024a-0ubuntu0.22.04", Type: pkg.DebPkg}}, "ubuntu", "22.04"},
+		{"rhel dist tag", []pkg.Package{rpm("openssl-libs", "1:3.0.7-25.el9_3"), rpm("glibc", "2.34-83.el9")}, "rhel", "9"},
+		{"amazon linux", []pkg.Package{rpm("glibc", "2.26-63.amzn2")}, "amzn", "2"},
+		{"alpine release package", []pkg.Package{{Name: "alpine-release", Version: "3.18.4-r0", Type: pkg.ApkPkg}}, "alpine", "3.18.4"},
+		{"wolfi baselayout", []pkg.Package{{Name: "wolfi-baselayout", Version: "20230201-r7", Type: pkg.ApkPkg}}, "wolfi", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inferred := distroFromPackages(tt.packages)
+			if inferred == nil {
+				t.Fatal("distroFromPackages() = nil")
+			}
+			if inferred.release.ID != tt.id || inferred.release.VersionID != tt.version {
+				t.Errorf("distroFromPackages() = %q %q, want %q %q", inferred.release.ID, inferred.release.VersionID, tt.id, tt.version)
+			}
+		})
+	}
+
+	if inferred := distroFromPackages([]pkg.Package{{Name: "requests", Version: "2.31.0", Type: pkg.PythonPkg}}); inferred != nil {
+		t.Errorf("distroFromPackages() without distro packages = %+v, want nil", inferred)
+	}
+}
+
+// TestCheckInferredOS tests that inferred distros are evaluated with lower confidence
+func TestCheckInferredOS(t *testing.T) {
+	scanner := newTestDBScanner(t, osProducts()...)
+
+	info := scanner.checkInferredOS(&inferredDistro{
+		release:    linux.Release{ID: "debian", VersionID: "12"},
+		evidence:   "/etc/debian_version=12.4",
+		confidence: ConfidenceMedium,
+	})
+	if !info.Inferred || info.MatchedCycle != "12" || info.PrettyName != "debian 12 (inferred)" {
+		t.Errorf("checkInferredOS() = %+v", info)
+	}
+	if info.Match == nil || info.Match.Method != MatchInferredOS || info.Match.Confidence != ConfidenceMedium || info.Match.Identifier != "/etc/debian_version=12.4" {
+		t.Errorf("match = %+v", info.Match)
+	}
+
+	if scanner.checkInferredOS(nil) != nil {
+		t.Error("checkInferredOS(nil) returned an OS")
+	}
+}
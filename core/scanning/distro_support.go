@@ -0,0 +1,112 @@
+package scanning
+
+import (
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// SupportWindow is the end of life of a component under one lifecycle: that of its
+// upstream project, or that of the distro release shipping it
+type SupportWindow struct {
+	Product      string    `json:"product"`
+	Cycle        string    `json:"cycle,omitempty"`
+	Status       EOLStatus `json:"status"`
+	EOLDate      string    `json:"eol_date,omitempty"`
+	DaysUntilEOL *int      `json:"days_until_eol,omitempty"`
+}
+
+// distroPackageTypes are the package types installed from a distro repository
+var distroPackageTypes = map[pkg.Type]bool{
+	pkg.DebPkg: true,
+	pkg.RpmPkg: true,
+	pkg.ApkPkg: true,
+}
+
+// distroSupport evaluates distro packages against the support of the distro release
+// that ships them. Distro vendors backport fixes into the runtimes and libraries they
+// ship (Python 3.6 on RHEL 8) until the release itself is EOL, regardless of upstream.
+type distroSupport struct {
+	os SupportWindow
+}
+
+// newDistroSupport prepares the distro support evaluation for an image's OS. It returns
+// nil unless the mode is enabled and the OS release has a known end of life.
+func (s *Scanner) newDistroSupport(osInfo *OSInfo) *distroSupport {
+	if !s.config.DistroSupport || osInfo == nil || osInfo.MatchedCycle == "" {
+		return nil
+	}
+	if osInfo.Status == StatusUnknown || osInfo.Status == StatusRolling {
+		return nil
+	}
+	return &distroSupport{os: SupportWindow{
+		Product:      osInfo.MatchedProduct,
+		Cycle:        osInfo.MatchedCycle,
+		Status:       osInfo.Status,
+		EOLDate:      osInfo.EOLDate,
+		DaysUntilEOL: osInfo.DaysUntilEOL,
+	}}
+}
+
+// apply replaces the upstream status of a distro package with that of its distro
+// release, keeping both lifecycles on the result. Packages built for an older release
+// are supported only as long as that release. The upstream support phases, discontinued
+// state, patch level and upgrade recommendations do not describe the distro build and
+// are cleared.
+func (d *distroSupport) apply(result *ComponentResult, p pkg.Package) {
+	if d == nil || !distroPackageTypes[p.Type] || result.Status == StatusUnknown {
+		return
+	}
+	if result.MatchedProduct == d.os.Product {
+		return
+	}
+
+	window := d.os
+	if o := result.OriginRelease; o != nil {
+		if o.Status == StatusUnknown {
+			return
+		}
+		window = SupportWindow{
+			Product:      o.Product,
+			Cycle:        o.Release,
+			Status:       o.Status,
+			EOLDate:      o.EOLDate,
+			DaysUntilEOL: o.DaysUntilEOL,
+		}
+	}
+
+	result.Upstream = &SupportWindow{
+		Product:      result.MatchedProduct,
+		Cycle:        result.MatchedCycle,
+		Status:       result.Status,
+		EOLDate:      result.EOLDate,
+		DaysUntilEOL: result.DaysUntilEOL,
+	}
+	result.DistroSupport = &window
+	result.Status = window.Status
+	result.EOLDate = window.EOLDate
+	result.DaysUntilEOL = window.DaysUntilEOL
+
+	result.SupportEndDate = ""
+	result.DaysUntilSupportEnd = nil
+	result.ExtendedSupportEndDate = ""
+	result.DaysUntilExtendedSupportEnd = nil
+	result.HasExtendedSupport = false
+	result.ExtendedSupportApplied = false
+	result.DiscontinuedDate = ""
+	result.IsDiscontinued = false
+	result.LatestVersion = ""
+	result.LatestReleaseDate = ""
+	result.OutdatedInCycle = false
+	result.ReleasesBehind = nil
+	result.DaysSinceLatestRelease = nil
+	result.Recommendations = nil
+}
+
+// DistroSupported reports whether a component is past its upstream end of life (or
+// approaching it) while its distro release still supports it
+func (result ComponentResult) DistroSupported() bool {
+	if result.Upstream == nil {
+		return false
+	}
+	upstreamEOL := result.Upstream.Status == StatusEOL || result.Upstream.Status == StatusEOLSoon
+	return upstreamEOL && result.Status != StatusEOL && result.Status != StatusEOLSoon
+}
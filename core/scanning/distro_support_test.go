@@ -0,0 +1,144 @@
+package scanning
+
+import (
+	"testing"
+
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// TestNewDistroSupport tests that distro support is only evaluated when enabled and the
+// OS release has an end of life
+func TestNewDistroSupport(t *testing.T) {
+	rhel := &OSInfo{ID: "rhel", MatchedProduct: "rhel", MatchedCycle: "8", Status: StatusActive, EOLDate: "2029-05-31"}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		os      *OSInfo
+		want    bool
+	}{
+		{"enabled", true, rhel, true},
+		{"disabled", false, rhel, false},
+		{"no OS", true, nil, false},
+		{"unmatched OS", true, &OSInfo{ID: "rhel", Status: StatusUnknown}, false},
+		{"rolling OS", true, &OSInfo{ID: "arch", MatchedProduct: "arch", MatchedCycle: "rolling", Status: StatusRolling}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Scanner{config: &ScannerConfig{DistroSupport: tt.enabled}}
+			if got := s.newDistroSupport(tt.os) != nil; got != tt.want {
+				t.Errorf("newDistroSupport() enabled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDistroSupportApply tests evaluating distro packages against their distro release
+func TestDistroSupportApply(t *testing.T) {
+	s := &Scanner{config: &ScannerConfig{DistroSupport: true}}
+	support := s.newDistroSupport(&OSInfo{
+		ID: "rhel", MatchedProduct: "rhel", MatchedCycle: "8", Status: StatusActive, EOLDate: "2029-05-31",
+	})
+	behind := 3
+	upstreamEOL := ComponentResult{
+		Name: "python36", MatchedProduct: "python", MatchedCycle: "3.6", Status: StatusEOL, EOLDate: "2021-12-23",
+		SupportEndDate: "2018-12-24", LatestVersion: "3.6.15", OutdatedInCycle: true, ReleasesBehind: &behind,
+		DiscontinuedDate: "2021-12-23", IsDiscontinued: true,
+		Recommendations: []Recommendation{{Strategy: StrategyNewestLTS, Cycle: "3.13"}},
+	}
+
+	tests := []struct {
+		name     string
+		result   ComponentResult
+		pkgType  pkg.Type
+		status   EOLStatus
+		eolDate  string
+		upstream bool
+	}{
+		{
+			name:    "rpm follows the distro release",
+			result:  upstreamEOL,
+			pkgType: pkg.RpmPkg,
+			status:  StatusActive, eolDate: "2029-05-31", upstream: true,
+		},
+		{
+			name:    "language package keeps its upstream status",
+			result:  upstreamEOL,
+			pkgType: pkg.PythonPkg,
+			status:  StatusEOL, eolDate: "2021-12-23",
+		},
+		{
+			name:    "unmatched package",
+			result:  ComponentResult{Name: "libfoo", Status: StatusUnknown},
+			pkgType: pkg.RpmPkg,
+			status:  StatusUnknown,
+		},
+		{
+			name: "package built for an older release follows that release",
+			result: ComponentResult{
+				Name: "python27", MatchedProduct: "python", MatchedCycle: "2.7", Status: StatusEOL, EOLDate: "2020-01-01",
+				OriginRelease: &OriginRelease{Distro: "rhel", Release: "7", Product: "rhel", Status: StatusEOL, EOLDate: "2024-06-30"},
+			},
+			pkgType: pkg.RpmPkg,
+			status:  StatusEOL, eolDate: "2024-06-30", upstream: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.result
+			support.apply(&result, pkg.Package{Name: result.Name, Type: tt.pkgType})
+
+			if result.Status != tt.status || result.EOLDate != tt.eolDate {
+				t.Errorf("status = %s (EOL %s), want %s (EOL %s)", result.Status, result.EOLDate, tt.status, tt.eolDate)
+			}
+			if (result.Upstream != nil) != tt.upstream {
+				t.Fatalf("Upstream = %+v, want set = %v", result.Upstream, tt.upstream)
+			}
+			if tt.upstream && (result.Upstream.Status != tt.result.Status || result.Upstream.EOLDate != tt.result.EOLDate) {
+				t.Errorf("Upstream = %+v, want the original %s status", result.Upstream, tt.result.Status)
+			}
+			if tt.upstream && (result.DistroSupport == nil || result.DistroSupport.EOLDate != tt.eolDate) {
+				t.Errorf("DistroSupport = %+v, want EOL %s", result.DistroSupport, tt.eolDate)
+			}
+			if tt.upstream && (result.Recommendations != nil || result.SupportEndDate != "" || result.OutdatedInCycle || result.ReleasesBehind != nil) {
+				t.Errorf("result kept upstream fields: recommendations %+v, support end %q, outdated %v",
+					result.Recommendations, result.SupportEndDate, result.OutdatedInCycle)
+			}
+			if tt.upstream && (result.IsDiscontinued || result.DiscontinuedDate != "") {
+				t.Errorf("result kept the upstream discontinued state (%v, %q)", result.IsDiscontinued, result.DiscontinuedDate)
+			}
+			if !tt.upstream && tt.result.Recommendations != nil && result.Recommendations == nil {
+				t.Error("apply() cleared the recommendations of a package it did not apply to")
+			}
+		})
+	}
+
+	var none *distroSupport
+	none.apply(&upstreamEOL, pkg.Package{Type: pkg.RpmPkg})
+	if upstreamEOL.Upstream != nil {
+		t.Error("apply() on a nil distroSupport changed the result")
+	}
+}
+
+// TestScanSummaryDistroSupported tests counting upstream EOL packages the distro supports
+func TestScanSummaryDistroSupported(t *testing.T) {
+	summary := &ScanSummary{}
+	summary.addComponent(ComponentResult{
+		Name: "python36", Status: StatusActive,
+		Upstream: &SupportWindow{Product: "python", Cycle: "3.6", Status: StatusEOL},
+	})
+	summary.addComponent(ComponentResult{
+		Name: "python27", Status: StatusEOL,
+		Upstream: &SupportWindow{Product: "python", Cycle: "2.7", Status: StatusEOL},
+	})
+	summary.addComponent(ComponentResult{Name: "python3.12", Status: StatusActive})
+
+	if summary.DistroSupportedComponents != 1 {
+		t.Errorf("DistroSupportedComponents = %d, want 1", summary.DistroSupportedComponents)
+	}
+	if summary.EOLComponents != 1 || summary.ActiveComponents != 2 {
+		t.Errorf("EOL/active = %d/%d, want 1/2", summary.EOLComponents, summary.ActiveComponents)
+	}
+}
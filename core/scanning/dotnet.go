@@ -0,0 +1,150 @@
+package scanning
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// sharedFrameworkPath matches files of an installed .NET shared framework, e.g.
+// /usr/share/dotnet/shared/Microsoft.AspNetCore.App/8.0.1/Microsoft.AspNetCore.dll
+var sharedFrameworkPath = regexp.MustCompile(`^(.*/)?shared/(Microsoft\.(?:NETCore|AspNetCore|WindowsDesktop)\.App)/(\d+\.\d+\.\d+[^/]*)/`)
+
+// dotnetRuntimePrefixes are the names under which deps.json files list the .NET and
+// ASP.NET Core runtimes of self-contained and framework-dependent apps
+var dotnetRuntimePrefixes = []string{
+	"Microsoft.NETCore.App",
+	"Microsoft.AspNetCore.App",
+	"runtimepack.Microsoft.NETCore.App.Runtime",
+	"runtimepack.Microsoft.AspNetCore.App.Runtime",
+}
+
+// dotnetFrameworkPackages are NuGet packages shipped with .NET whose versions follow the
+// .NET release they belong to, so they share its support cycle
+var dotnetFrameworkPackages = []string{
+	"Microsoft.EntityFrameworkCore",
+	"Microsoft.AspNetCore.",
+	"Microsoft.Extensions.",
+}
+
+// dotnetIndependentPackages are packages under the framework prefixes that are versioned
+// independently of .NET, e.g. Microsoft.Extensions.Azure 1.x is not .NET 1.0
+var dotnetIndependentPackages = []string{
+	"Microsoft.AspNetCore.OData",
+	"Microsoft.AspNetCore.Mvc.Versioning",
+	"Microsoft.Extensions.AI",
+	"Microsoft.Extensions.Azure",
+	"Microsoft.Extensions.Configuration.AzureAppConfiguration",
+	"Microsoft.Extensions.Http.Resilience",
+	"Microsoft.Extensions.Logging.ApplicationInsights",
+	"Microsoft.Extensions.Resilience",
+	"Microsoft.Extensions.ServiceDiscovery",
+}
+
+// dotnetFramework is an installed .NET shared framework, e.g. the ASP.NET Core runtime
+type dotnetFramework struct {
+	name      string
+	version   string
+	dirs      []string
+	locations []file.Location
+}
+
+// dotnetFrameworks collects the shared frameworks installed in an image from the
+// locations of the packages found in them, ordered by name and version
+func dotnetFrameworks(packages []pkg.Package) []dotnetFramework {
+	type frameworkKey struct{ name, version string }
+	byKey := make(map[frameworkKey]*dotnetFramework)
+
+	for _, p := range packages {
+		name, version, dir, ok := sharedFrameworkOf(p)
+		if !ok {
+			continue
+		}
+		key := frameworkKey{name, version}
+		framework, ok := byKey[key]
+		if !ok {
+			framework = &dotnetFramework{name: name, version: version}
+			byKey[key] = framework
+		}
+		if !slices.Contains(framework.dirs, dir) {
+			framework.dirs = append(framework.dirs, dir)
+		}
+		for _, location := range p.Locations.ToSlice() {
+			if strings.HasPrefix(location.RealPath, dir+"/") {
+				framework.locations = append(framework.locations, location)
+			}
+		}
+	}
+
+	frameworks := make([]dotnetFramework, 0, len(byKey))
+	for _, framework := range byKey {
+		sort.Strings(framework.dirs)
+		frameworks = append(frameworks, *framework)
+	}
+	sort.Slice(frameworks, func(i, j int) bool {
+		if frameworks[i].name != frameworks[j].name {
+			return frameworks[i].name < frameworks[j].name
+		}
+		return ParseVersion(frameworks[i].version, SchemeSemver).Compare(
+			ParseVersion(frameworks[j].version, SchemeSemver)) < 0
+	})
+	return frameworks
+}
+
+// pkg returns the component evaluated for the shared framework
+func (f dotnetFramework) pkg() pkg.Package {
+	return pkg.Package{
+		Name:      f.name,
+		Version:   f.version,
+		Type:      pkg.DotnetPkg,
+		PURL:      "pkg:nuget/" + f.name + "@" + f.version,
+		Locations: file.NewLocationSet(f.locations...),
+	}
+}
+
+// sharedFrameworkOf reports whether a package was found inside a .NET shared framework
+// directory, and which framework, version and directory it belongs to. Such packages
+// are the assemblies of the runtime and are reported through the framework instead.
+func sharedFrameworkOf(p pkg.Package) (string, string, string, bool) {
+	for _, location := range p.Locations.ToSlice() {
+		match := sharedFrameworkPath.FindStringSubmatch(location.RealPath)
+		if match == nil {
+			continue
+		}
+		dir := strings.TrimSuffix(match[0], "/")
+		return match[2], match[3], dir, true
+	}
+	return "", "", "", false
+}
+
+// isDotnetRuntime reports whether a .NET package name refers to the .NET or ASP.NET Core
+// runtime, e.g. Microsoft.NETCore.App.Runtime.linux-x64 or runtime.linux-x64.Microsoft.NETCore.App
+func isDotnetRuntime(name string) bool {
+	for _, prefix := range dotnetRuntimePrefixes {
+		if name == prefix || strings.HasPrefix(name, prefix+".") {
+			return true
+		}
+	}
+	return strings.HasPrefix(name, "runtime.") &&
+		(strings.HasSuffix(name, ".Microsoft.NETCore.App") || strings.HasSuffix(name, ".Microsoft.AspNetCore.App"))
+}
+
+// isDotnetFrameworkPackage reports whether a NuGet package ships with .NET and follows
+// its release cycle
+func isDotnetFrameworkPackage(name string) bool {
+	for _, independent := range dotnetIndependentPackages {
+		if strings.EqualFold(name, independent) || strings.HasPrefix(strings.ToLower(name), strings.ToLower(independent)+".") {
+			return false
+		}
+	}
+	for _, prefix := range dotnetFrameworkPackages {
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package scanning
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// dotnetPackage builds a .NET package found at the given path
+func dotnetPackage(name, version, path string) pkg.Package {
+	return pkg.Package{
+		Name:      name,
+		Version:   version,
+		Type:      pkg.DotnetPkg,
+		Locations: file.NewLocationSet(file.NewLocation(path)),
+	}
+}
+
+// TestDotnetFrameworks tests collecting installed shared frameworks from package locations
+func TestDotnetFrameworks(t *testing.T) {
+	packages := []pkg.Package{
+		dotnetPackage("Microsoft.NETCore.App", "8.0.1", "/usr/share/dotnet/shared/Microsoft.NETCore.App/8.0.1/System.Private.CoreLib.dll"),
+		dotnetPackage("Microsoft.AspNetCore.Http", "8.0.1", "/usr/share/dotnet/shared/Microsoft.AspNetCore.App/8.0.1/Microsoft.AspNetCore.Http.dll"),
+		dotnetPackage("Microsoft.AspNetCore.Mvc.Core", "8.0.1", "/usr/share/dotnet/shared/Microsoft.AspNetCore.App/8.0.1/Microsoft.AspNetCore.Mvc.Core.dll"),
+		dotnetPackage("Microsoft.NETCore.App", "6.0.25", "/opt/dotnet6/shared/Microsoft.NETCore.App/6.0.25/System.Runtime.dll"),
+		dotnetPackage("Newtonsoft.Json", "13.0.3", "/app/Newtonsoft.Json.dll"),
+	}
+
+	frameworks := dotnetFrameworks(packages)
+
+	want := []struct {
+		name    string
+		version string
+		dirs    []string
+	}{
+		{"Microsoft.AspNetCore.App", "8.0.1", []string{"/usr/share/dotnet/shared/Microsoft.AspNetCore.App/8.0.1"}},
+		{"Microsoft.NETCore.App", "6.0.25", []string{"/opt/dotnet6/shared/Microsoft.NETCore.App/6.0.25"}},
+		{"Microsoft.NETCore.App", "8.0.1", []string{"/usr/share/dotnet/shared/Microsoft.NETCore.App/8.0.1"}},
+	}
+	if len(frameworks) != len(want) {
+		t.Fatalf("dotnetFrameworks() returned %d frameworks, want %d: %+v", len(frameworks), len(want), frameworks)
+	}
+	for i, w := range want {
+		f := frameworks[i]
+		if f.name != w.name || f.version != w.version || !slices.Equal(f.dirs, w.dirs) {
+			t.Errorf("framework %d = %+v, want %+v", i, f, w)
+		}
+	}
+
+	if _, _, _, ok := sharedFrameworkOf(packages[4]); ok {
+		t.Error("sharedFrameworkOf() matched an application assembly")
+	}
+}
+
+// TestDotnetRuntimeProduct tests mapping .NET runtimes and framework packages to dotnet
+func TestDotnetRuntimeProduct(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Microsoft.NETCore.App", true},
+		{"Microsoft.AspNetCore.App", true},
+		{"Microsoft.NETCore.App.Runtime.linux-x64", true},
+		{"runtimepack.Microsoft.NETCore.App.Runtime.linux-musl-x64", true},
+		{"runtimepack.Microsoft.AspNetCore.App.Runtime.linux-x64", true},
+		{"runtime.linux-x64.Microsoft.NETCore.App", true},
+		{"Microsoft.EntityFrameworkCore", true},
+		{"Microsoft.EntityFrameworkCore.SqlServer", true},
+		{"Microsoft.AspNetCore.Authentication.JwtBearer", true},
+		{"Microsoft.Extensions.Logging", true},
+		{"Microsoft.AspNetCore.OData", false},
+		{"Microsoft.AspNetCore.Mvc.Versioning.ApiExplorer", false},
+		{"Microsoft.Extensions.Azure", false},
+		{"Microsoft.Extensions.Configuration.AzureAppConfiguration", false},
+		{"Microsoft.Extensions.Http.Resilience", false},
+		{"Npgsql.EntityFrameworkCore.PostgreSQL", false},
+		{"Newtonsoft.Json", false},
+		{"Microsoft.NETCore.Platforms", false},
+	}
+
+	for _, tt := range tests {
+		product, version, ok := runtimeProduct(pkg.Package{Name: tt.name, Version: "8.0.1", Type: pkg.DotnetPkg})
+		if ok != tt.want {
+			t.Errorf("runtimeProduct(%q) ok = %v, want %v", tt.name, ok, tt.want)
+			continue
+		}
+		if ok && (product != "dotnet" || version != "8.0.1") {
+			t.Errorf("runtimeProduct(%q) = %q, %q, want dotnet 8.0.1", tt.name, product, version)
+		}
+	}
+
+	if _, _, ok := runtimeProduct(pkg.Package{Name: "Microsoft.NETCore.App", Type: pkg.NpmPkg}); ok {
+		t.Error("runtimeProduct() matched a non-.NET package")
+	}
+}
+
+// TestDotnetFrameworkPackage tests the component evaluated for a shared framework
+func TestDotnetFrameworkPackage(t *testing.T) {
+	p := dotnetFramework{name: "Microsoft.AspNetCore.App", version: "8.0.1"}.pkg()
+
+	if p.PURL != "pkg:nuget/Microsoft.AspNetCore.App@8.0.1" || p.Type != pkg.DotnetPkg {
+		t.Errorf("pkg() = %+v", p)
+	}
+	if product, _, ok := runtimeProduct(p); !ok || product != "dotnet" {
+		t.Errorf("runtimeProduct() = %q, %v, want dotnet", product, ok)
+	}
+}
@@ -0,0 +1,155 @@
+package scanning
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+)
+
+// MatchAttempt records one lookup of the matching chain and its outcome
+type MatchAttempt struct {
+	Matcher      string             `json:"matcher"`
+	Method       MatchMethod        `json:"method"`
+	Query        string             `json:"query,omitempty"`
+	Matched      bool               `json:"matched"`
+	Selected     bool               `json:"selected"`
+	Product      string             `json:"product,omitempty"`
+	Identifier   string             `json:"identifier,omitempty"`
+	Confidence   MatchConfidence    `json:"confidence,omitempty"`
+	Reason       string             `json:"reason"`
+	Alternatives []MatchAlternative `json:"alternatives,omitempty"` // Other products the lookup matched
+}
+
+// ComponentExplanation describes how a package was matched against the EOL database
+type ComponentExplanation struct {
+	Name     string          `json:"name"`
+	Version  string          `json:"version"`
+	Type     string          `json:"type"`
+	PURL     string          `json:"purl,omitempty"`
+	CPEs     []string        `json:"cpes,omitempty"`
+	Attempts []MatchAttempt  `json:"attempts"`
+	Result   ComponentResult `json:"result"`
+}
+
+// ExplainFromTar explains the matching of a package in a container image tar archive
+func (s *Scanner) ExplainFromTar(ctx context.Context, tarPath, packageName string) ([]ComponentExplanation, error) {
+	if err := s.ensureDatabase(ctx); err != nil {
+		return nil, err
+	}
+
+	s.progress("sbom", "Generating SBOM from tar archive...")
+	sbomResult, err := s.generator.GenerateFromTar(ctx, tarPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate SBOM: %w", err)
+	}
+
+	return s.explainSBOM(sbomResult, tarPath, packageName)
+}
+
+// ExplainFromRegistry explains the matching of a package in a registry image
+func (s *Scanner) ExplainFromRegistry(ctx context.Context, imageRef, packageName string) ([]ComponentExplanation, error) {
+	if err := s.ensureDatabase(ctx); err != nil {
+		return nil, err
+	}
+
+	s.progress("sbom", "Generating SBOM from registry image...")
+	sbomResult, err := s.generator.GenerateFromRegistry(ctx, imageRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate SBOM: %w", err)
+	}
+
+	return s.explainSBOM(sbomResult, imageRef, packageName)
+}
+
+// ExplainFromDocker explains the matching of a package in a local Docker image
+func (s *Scanner) ExplainFromDocker(ctx context.Context, imageRef, packageName string) ([]ComponentExplanation, error) {
+	if err := s.ensureDatabase(ctx); err != nil {
+		return nil, err
+	}
+
+	s.progress("sbom", "Generating SBOM from Docker image...")
+	sbomResult, err := s.generator.GenerateFromDocker(ctx, imageRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate SBOM: %w", err)
+	}
+
+	return s.explainSBOM(sbomResult, imageRef, packageName)
+}
+
+// matchTrace records the lookups made for each package of a scan being explained
+type matchTrace struct {
+	packages []tracedPackage
+}
+
+// tracedPackage is a package checked against the database and the lookups made for it
+type tracedPackage struct {
+	pkg      pkg.Package
+	attempts []MatchAttempt
+}
+
+// add records the lookups made for a package; it does nothing when no scan is explained
+func (t *matchTrace) add(p pkg.Package, attempts []MatchAttempt) {
+	if t == nil {
+		return
+	}
+	t.packages = append(t.packages, tracedPackage{pkg: p, attempts: attempts})
+}
+
+// explainSBOM scans the SBOM as a scan would, tracing the lookups of each package, and
+// explains every checked package whose name or PURL matches packageName. These are the
+// packages a scan checks: the Go toolchains and .NET runtimes it derives replace the
+// stdlib and shared framework entries of the SBOM.
+func (s *Scanner) explainSBOM(sbomResult *sbom.SBOM, source, packageName string) ([]ComponentExplanation, error) {
+	s.trace = &matchTrace{}
+	defer func() { s.trace = nil }()
+
+	summary, err := s.analyzeSBOM(sbomResult, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var explanations []ComponentExplanation
+	for _, traced := range s.trace.packages {
+		p := traced.pkg
+		if !strings.EqualFold(p.Name, packageName) && p.PURL != packageName {
+			continue
+		}
+		explanation := ComponentExplanation{
+			Name:     p.Name,
+			Version:  p.Version,
+			Type:     string(p.Type),
+			PURL:     p.PURL,
+			Attempts: traced.attempts,
+			Result:   reportedResult(summary.Components, p),
+		}
+		for _, c := range p.CPEs {
+			explanation.CPEs = append(explanation.CPEs, c.Attributes.String())
+		}
+		explanations = append(explanations, explanation)
+	}
+	return explanations, nil
+}
+
+// reportedResult returns the result a scan reported a package as: its own, or that of
+// the source package its binary was merged into
+func reportedResult(components []ComponentResult, p pkg.Package) ComponentResult {
+	for _, c := range components {
+		if c.Type != string(p.Type) || c.Version != p.Version {
+			continue
+		}
+		if c.SourcePackage != "" && len(c.Binaries) > 0 {
+			if slices.Contains(c.Binaries, p.Name) {
+				return c
+			}
+			continue
+		}
+		if c.Name == p.Name {
+			return c
+		}
+	}
+	return newComponentResult(p)
+}
@@ -0,0 +1,99 @@
+package scanning
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/anchore/syft/syft/linux"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/j0356/eol-scanner/core/db"
+)
+
+// explainPackage explains a package scanned on its own
+func explainPackage(t *testing.T, scanner *Scanner, p pkg.Package) ComponentExplanation {
+	t.Helper()
+	explanations, err := scanner.explainSBOM(&sbom.SBOM{Artifacts: sbom.Artifacts{Packages: pkg.NewCollection(p)}}, "test", p.Name)
+	if err != nil {
+		t.Fatalf("explainSBOM() error = %v", err)
+	}
+	if len(explanations) != 1 {
+		t.Fatalf("explainSBOM() returned %d explanations, want 1", len(explanations))
+	}
+	return explanations[0]
+}
+
+// TestExplainSBOM tests that explanations report the results of a scan, after Go
+// toolchains are derived, distro support is applied and binaries are merged into
+// their source package
+func TestExplainSBOM(t *testing.T) {
+	eol := true
+	products := append(osProducts(),
+		db.ProductData{
+			Name:        "openssl",
+			Category:    "lang",
+			Identifiers: []db.Identifier{{Type: "purl", ID: "pkg:deb/debian/openssl"}},
+			Releases: []db.ReleaseData{
+				{Name: "1.1.1", ReleaseDate: "2018-09-11", IsEol: &eol, EolFrom: "2023-09-11"},
+			},
+		},
+		db.ProductData{Name: "go", Category: "lang", Releases: []db.ReleaseData{
+			{Name: "1.19", ReleaseDate: "2022-08-02", IsEol: &eol, EolFrom: "2023-08-08"},
+		}},
+	)
+	scanner := newTestDBScanner(t, products...)
+	scanner.config.DistroSupport = true
+
+	packages := []pkg.Package{
+		goModule("github.com/acme/cli", "/usr/local/bin/cli", "go1.19.13"),
+		goModule("stdlib", "/usr/local/bin/cli", "go1.19.13"),
+	}
+	for _, name := range []string{"libssl1.1", "libssl-dev"} {
+		packages = append(packages, pkg.Package{
+			Name:     name,
+			Version:  "1.1.1w-0+deb12u1",
+			Type:     pkg.DebPkg,
+			PURL:     "pkg:deb/debian/" + name + "@1.1.1w-0+deb12u1?distro=debian-12",
+			Metadata: pkg.DpkgDBEntry{Package: name, Source: "openssl"},
+		})
+	}
+	sbomResult := &sbom.SBOM{Artifacts: sbom.Artifacts{
+		Packages:          pkg.NewCollection(packages...),
+		LinuxDistribution: &linux.Release{ID: "debian", VersionID: "12"},
+	}}
+
+	explanations, err := scanner.explainSBOM(sbomResult, "test", "libssl-dev")
+	if err != nil {
+		t.Fatalf("explainSBOM() error = %v", err)
+	}
+	if len(explanations) != 1 {
+		t.Fatalf("explainSBOM(libssl-dev) returned %d explanations, want 1", len(explanations))
+	}
+	e := explanations[0]
+	if e.Name != "libssl-dev" || len(e.Attempts) == 0 {
+		t.Errorf("explanation = %s with %d attempts, want libssl-dev with its lookups", e.Name, len(e.Attempts))
+	}
+	if want := []string{"libssl-dev", "libssl1.1"}; e.Result.Name != "openssl" || !slices.Equal(e.Result.Binaries, want) {
+		t.Errorf("result = %s %v, want openssl merged from %v", e.Result.Name, e.Result.Binaries, want)
+	}
+	if e.Result.DistroSupport == nil || e.Result.Status != StatusActive {
+		t.Errorf("result status = %s (distro support %+v), want the active Debian 12 support", e.Result.Status, e.Result.DistroSupport)
+	}
+
+	// Go binaries are explained through the toolchain that built them
+	explanations, err = scanner.explainSBOM(sbomResult, "test", "go")
+	if err != nil {
+		t.Fatalf("explainSBOM() error = %v", err)
+	}
+	if len(explanations) != 1 || explanations[0].Result.MatchedCycle != "1.19" ||
+		!slices.Equal(explanations[0].Result.Binaries, []string{"/usr/local/bin/cli"}) {
+		t.Errorf("explainSBOM(go) = %+v, want the go 1.19 toolchain of /usr/local/bin/cli", explanations)
+	}
+	if explanations, _ := scanner.explainSBOM(sbomResult, "test", "stdlib"); len(explanations) != 0 {
+		t.Errorf("explainSBOM(stdlib) = %+v, want none since the toolchain replaces it", explanations)
+	}
+
+	if scanner.trace != nil {
+		t.Error("explainSBOM() left the trace on the scanner")
+	}
+}
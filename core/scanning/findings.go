@@ -0,0 +1,124 @@
+package scanning
+
+// Finding groups the components that resolve to the same product and cycle, e.g. the
+// dozens of libpython3.9 and python3.9 packages of a Debian image, into one result
+type Finding struct {
+	Product                string             `json:"product"`
+	Cycle                  string             `json:"cycle,omitempty"`
+	Status                 EOLStatus          `json:"status"`
+	EOLDate                string             `json:"eol_date,omitempty"`
+	DaysUntilEOL           *int               `json:"days_until_eol,omitempty"`
+	SupportEndDate         string             `json:"support_end_date,omitempty"`
+	ExtendedSupportEndDate string             `json:"extended_support_end_date,omitempty"`
+	IsLTS                  bool               `json:"is_lts"`
+	Recommendations        []Recommendation   `json:"recommendation,omitempty"`
+	PackageCount           int                `json:"package_count"`
+	Components             []FindingComponent `json:"components"`
+}
+
+// FindingComponent is a package contributing to a finding
+type FindingComponent struct {
+	Name        string      `json:"name"`
+	Version     string      `json:"version"`
+	PURL        string      `json:"purl,omitempty"`
+	Type        string      `json:"type"`
+	Status      EOLStatus   `json:"status"`
+	Binaries    []string    `json:"binaries,omitempty"`
+	LayerDigest string      `json:"layer_digest,omitempty"`
+	LayerSource LayerSource `json:"layer_source,omitempty"`
+}
+
+// statusSeverity orders statuses so a finding reports its most severe component
+var statusSeverity = map[EOLStatus]int{
+	StatusUnknown:      0,
+	StatusActive:       1,
+	StatusRolling:      1,
+	StatusSecurityOnly: 2,
+	StatusEOLSoon:      3,
+	StatusEOL:          4,
+}
+
+// GroupFindings groups matched components by product and cycle. A finding takes the
+// dates and status of its most severe component; components that were not matched to
+// a product are left out. Findings keep the order of their first component.
+func GroupFindings(components []ComponentResult) []Finding {
+	type findingKey struct{ product, cycle string }
+
+	findings := make([]Finding, 0)
+	index := make(map[findingKey]int)
+	for _, c := range components {
+		if c.MatchedProduct == "" {
+			continue
+		}
+
+		key := findingKey{c.MatchedProduct, c.MatchedCycle}
+		i, ok := index[key]
+		if !ok {
+			i = len(findings)
+			index[key] = i
+			findings = append(findings, Finding{Product: c.MatchedProduct, Cycle: c.MatchedCycle, Status: StatusUnknown})
+		}
+
+		f := &findings[i]
+		if len(f.Components) == 0 || statusSeverity[c.Status] > statusSeverity[f.Status] {
+			f.Status = c.Status
+			f.EOLDate = c.EOLDate
+			f.DaysUntilEOL = c.DaysUntilEOL
+			f.SupportEndDate = c.SupportEndDate
+			f.ExtendedSupportEndDate = c.ExtendedSupportEndDate
+			f.IsLTS = c.IsLTS
+		}
+		if len(f.Recommendations) == 0 {
+			f.Recommendations = c.Recommendations
+		}
+		f.Components = append(f.Components, FindingComponent{
+			Name:        c.Name,
+			Version:     c.Version,
+			PURL:        c.PURL,
+			Type:        c.Type,
+			Status:      c.Status,
+			Binaries:    c.Binaries,
+			LayerDigest: c.LayerDigest,
+			LayerSource: c.LayerSource,
+		})
+
+		// Binaries merged into a source package are packages of their own; the
+		// binaries of a Go toolchain and the directories of a .NET runtime are not
+		packages := 1
+		if c.SourcePackage != "" {
+			packages = max(1, len(c.Binaries))
+		}
+		f.PackageCount += packages
+	}
+	return findings
+}
+
+// addFinding appends a finding and updates the distinct product-cycle counts
+func (summary *ScanSummary) addFinding(finding Finding) {
+	summary.Findings = append(summary.Findings, finding)
+	summary.TotalFindings++
+
+	switch finding.Status {
+	case StatusEOL:
+		summary.EOLFindings++
+	case StatusEOLSoon:
+		summary.EOLSoonFindings++
+	case StatusSecurityOnly:
+		summary.SecurityOnlyFindings++
+	case StatusActive:
+		summary.ActiveFindings++
+	case StatusRolling:
+		summary.RollingFindings++
+	}
+}
+
+// GetEOLFindings returns only the findings that are EOL or EOL soon
+func (summary *ScanSummary) GetEOLFindings() []Finding {
+	var results []Finding
+	for _, f := range summary.Findings {
+		if f.Status == StatusEOL || f.Status == StatusEOLSoon {
+			results = append(results, f)
+		}
+	}
+	return results
+}
@@ -0,0 +1,88 @@
+package scanning
+
+import "testing"
+
+// TestGroupFindings tests grouping components by product and cycle
+func TestGroupFindings(t *testing.T) {
+	days := -400
+	components := []ComponentResult{
+		{Name: "Debian GNU/Linux 11", Type: "os", Status: StatusSecurityOnly, MatchedProduct: "debian", MatchedCycle: "11"},
+		{Name: "libpython3.9", Type: "deb", Status: StatusEOL, MatchedProduct: "python", MatchedCycle: "3.9", EOLDate: "2025-10-31", DaysUntilEOL: &days},
+		{Name: "python3.9-minimal", Type: "deb", Status: StatusEOL, MatchedProduct: "python", MatchedCycle: "3.9", EOLDate: "2025-10-31"},
+		{Name: "openssl", Type: "deb", Status: StatusActive, MatchedProduct: "openssl", MatchedCycle: "1.1.1", SourcePackage: "openssl", Binaries: []string{"libssl1.1", "openssl"}},
+		{Name: "libfoo", Type: "deb", Status: StatusUnknown},
+		{Name: "python3.9", Type: "deb", Status: StatusEOL, MatchedProduct: "python", MatchedCycle: "3.9", EOLDate: "2025-10-31"},
+		{Name: "python", Type: "binary", Status: StatusActive, MatchedProduct: "python", MatchedCycle: "3.12"},
+		{Name: "go", Type: "binary", Status: StatusEOL, MatchedProduct: "go", MatchedCycle: "1.20", Binaries: []string{"/usr/bin/app", "/usr/bin/cli"}},
+	}
+
+	findings := GroupFindings(components)
+
+	want := []struct {
+		product  string
+		cycle    string
+		status   EOLStatus
+		packages int
+		names    int
+	}{
+		{"debian", "11", StatusSecurityOnly, 1, 1},
+		{"python", "3.9", StatusEOL, 3, 3},
+		{"openssl", "1.1.1", StatusActive, 2, 1},
+		{"python", "3.12", StatusActive, 1, 1},
+		{"go", "1.20", StatusEOL, 1, 1},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("GroupFindings() returned %d findings, want %d: %+v", len(findings), len(want), findings)
+	}
+	for i, w := range want {
+		f := findings[i]
+		if f.Product != w.product || f.Cycle != w.cycle || f.Status != w.status {
+			t.Errorf("finding %d = %s %s (%s), want %s %s (%s)", i, f.Product, f.Cycle, f.Status, w.product, w.cycle, w.status)
+		}
+		if f.PackageCount != w.packages || len(f.Components) != w.names {
+			t.Errorf("finding %d counts = %d packages, %d components, want %d, %d", i, f.PackageCount, len(f.Components), w.packages, w.names)
+		}
+	}
+	if findings[1].EOLDate != "2025-10-31" || findings[1].DaysUntilEOL == nil || *findings[1].DaysUntilEOL != days {
+		t.Errorf("python 3.9 finding dates = %q, %v", findings[1].EOLDate, findings[1].DaysUntilEOL)
+	}
+}
+
+// TestGroupFindingsMostSevereStatus tests that a finding reports its most severe component
+func TestGroupFindingsMostSevereStatus(t *testing.T) {
+	findings := GroupFindings([]ComponentResult{
+		{Name: "a", Status: StatusActive, MatchedProduct: "nodejs", MatchedCycle: "18", EOLDate: "2025-04-30"},
+		{Name: "b", Status: StatusEOLSoon, MatchedProduct: "nodejs", MatchedCycle: "18", EOLDate: "2025-04-30", IsLTS: true},
+		{Name: "c", Status: StatusActive, MatchedProduct: "nodejs", MatchedCycle: "18"},
+	})
+
+	if len(findings) != 1 {
+		t.Fatalf("GroupFindings() returned %d findings, want 1", len(findings))
+	}
+	if findings[0].Status != StatusEOLSoon || !findings[0].IsLTS {
+		t.Errorf("finding = %s (LTS %v), want %s (LTS true)", findings[0].Status, findings[0].IsLTS, StatusEOLSoon)
+	}
+	if findings[0].Components[2].Status != StatusActive {
+		t.Errorf("component status = %s, want %s", findings[0].Components[2].Status, StatusActive)
+	}
+}
+
+// TestScanSummaryAddFinding tests that addFinding keeps the distinct counts in sync
+func TestScanSummaryAddFinding(t *testing.T) {
+	summary := &ScanSummary{}
+
+	for _, status := range []EOLStatus{StatusEOL, StatusEOL, StatusEOLSoon, StatusSecurityOnly, StatusActive, StatusUnknown} {
+		summary.addFinding(Finding{Product: "p", Status: status})
+	}
+
+	if summary.TotalFindings != 6 || len(summary.Findings) != 6 {
+		t.Errorf("TotalFindings = %d (%d findings), want 6", summary.TotalFindings, len(summary.Findings))
+	}
+	if summary.EOLFindings != 2 || summary.EOLSoonFindings != 1 || summary.SecurityOnlyFindings != 1 || summary.ActiveFindings != 1 {
+		t.Errorf("unexpected counts: eol=%d soon=%d security=%d active=%d",
+			summary.EOLFindings, summary.EOLSoonFindings, summary.SecurityOnlyFindings, summary.ActiveFindings)
+	}
+	if got := len(summary.GetEOLFindings()); got != 3 {
+		t.Errorf("len(GetEOLFindings()) = %d, want 3", got)
+	}
+}
@@ -0,0 +1,155 @@
+package scanning
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// mavenFramework maps Maven artifacts to an endoflife.date product. The group must match
+// exactly and the artifact is a glob pattern; products are tried in order. Artifacts
+// matching an exclude pattern are versioned independently of the framework.
+type mavenFramework struct {
+	group    string
+	artifact string
+	exclude  []string
+	products []string
+}
+
+// hibernateIndependentArtifacts are artifacts of the org.hibernate groups that have
+// their own release cycle, e.g. hibernate-commons-annotations 5.1 is not ORM 5.1
+var hibernateIndependentArtifacts = []string{
+	"hibernate-commons-annotations",
+	"hibernate-jpa-*",
+	"hibernate-ogm*",
+	"hibernate-reactive*",
+	"hibernate-search*",
+	"hibernate-validator*",
+}
+
+// mavenFrameworks lists the frameworks matched by their Maven coordinates. Matching on
+// the group keeps unrelated artifacts that share a name (e.g. a fork's spring-core)
+// from being reported as the framework.
+var mavenFrameworks = []mavenFramework{
+	{group: "org.springframework.boot", artifact: "spring-boot*", products: []string{"spring-boot"}},
+	{group: "org.springframework", artifact: "spring-*", products: []string{"spring-framework"}},
+	{group: "org.hibernate.orm", artifact: "hibernate-*", exclude: hibernateIndependentArtifacts, products: []string{"hibernate-orm", "hibernate"}},
+	{group: "org.hibernate", artifact: "hibernate-*", exclude: hibernateIndependentArtifacts, products: []string{"hibernate-orm", "hibernate"}},
+	{group: "org.apache.tomcat.embed", artifact: "tomcat-embed-*", products: []string{"tomcat"}},
+	{group: "org.apache.tomcat", artifact: "tomcat-*", products: []string{"tomcat"}},
+	{group: "org.apache.logging.log4j", artifact: "log4j-*", exclude: []string{"log4j-api-kotlin*", "log4j-api-scala*"}, products: []string{"log4j"}},
+	{group: "log4j", artifact: "log4j", products: []string{"log4j"}},
+	{group: "com.fasterxml.jackson.core", artifact: "jackson-*", products: []string{"jackson"}},
+}
+
+// javaVendors maps the IMPLEMENTOR of a JDK release file to the endoflife.date product
+// of that distribution. Matched case-insensitively on a substring, in order.
+var javaVendors = []struct {
+	implementor string
+	product     string
+}{
+	{"adoptium", "eclipse-temurin"},
+	{"adoptopenjdk", "eclipse-temurin"},
+	{"amazon", "amazon-corretto"},
+	{"azul", "azul-zulu"},
+	{"red hat", "redhat-build-of-openjdk"},
+	{"microsoft", "microsoft-build-of-openjdk"},
+	{"bellsoft", "bellsoft-liberica"},
+	{"sap", "sapmachine"},
+	{"ibm", "ibm-semeru-runtime"},
+	{"openj9", "ibm-semeru-runtime"},
+	{"graalvm", "graalvm"},
+	{"oracle", "oracle-jdk"},
+}
+
+// javaVendorMarkers are the markers a product also needs in IMPLEMENTOR_VERSION or
+// JAVA_RUNTIME_VERSION. Oracle is the implementor of upstream OpenJDK builds too, but
+// only Oracle JDK marks its releases LTS.
+var javaVendorMarkers = map[string][]string{
+	"oracle-jdk": {"-lts", "oracle"},
+}
+
+// mavenCoordinates returns the groupId and artifactId of a Java archive, from its Maven
+// PURL or, failing that, from the pom.properties of the archive
+func mavenCoordinates(p pkg.Package) (string, string) {
+	if rest, ok := strings.CutPrefix(p.PURL, "pkg:maven/"); ok {
+		if idx := strings.IndexAny(rest, "@?#"); idx >= 0 {
+			rest = rest[:idx]
+		}
+		if group, artifact, ok := strings.Cut(rest, "/"); ok && group != "" && artifact != "" {
+			return group, artifact
+		}
+	}
+
+	var archive *pkg.JavaArchive
+	switch m := p.Metadata.(type) {
+	case pkg.JavaArchive:
+		archive = &m
+	case *pkg.JavaArchive:
+		archive = m
+	}
+	if archive != nil && archive.PomProperties != nil && archive.PomProperties.GroupID != "" {
+		return archive.PomProperties.GroupID, archive.PomProperties.ArtifactID
+	}
+	return "", ""
+}
+
+// findMavenFramework returns the framework a Maven artifact belongs to, if it is known
+func findMavenFramework(group, artifact string) *mavenFramework {
+	if group == "" {
+		return nil
+	}
+	for i := range mavenFrameworks {
+		f := &mavenFrameworks[i]
+		if !strings.EqualFold(f.group, group) || !globMatch(f.artifact, artifact) {
+			continue
+		}
+		if slices.ContainsFunc(f.exclude, func(pattern string) bool { return globMatch(pattern, artifact) }) {
+			return nil
+		}
+		return f
+	}
+	return nil
+}
+
+// javaRelease returns the release information of a JVM installation found by syft
+func javaRelease(p pkg.Package) (pkg.JavaVMRelease, bool) {
+	switch m := p.Metadata.(type) {
+	case pkg.JavaVMInstallation:
+		return m.Release, true
+	case *pkg.JavaVMInstallation:
+		return m.Release, true
+	default:
+		return pkg.JavaVMRelease{}, false
+	}
+}
+
+// javaVendorProduct returns the product of a JVM distribution from its release file.
+// Builds without a known vendor (distro builds such as Debian's, upstream OpenJDK
+// builds) follow the OpenJDK updates project and are evaluated against Eclipse Temurin.
+func javaVendorProduct(release pkg.JavaVMRelease) string {
+	implementor := strings.ToLower(release.Implementor)
+	versions := strings.ToLower(release.ImplementorVersion + " " + release.JavaRuntimeVersion)
+	for _, v := range javaVendors {
+		if !strings.Contains(implementor, v.implementor) {
+			continue
+		}
+		markers := javaVendorMarkers[v.product]
+		if len(markers) == 0 || slices.ContainsFunc(markers, func(marker string) bool {
+			return strings.Contains(versions, marker)
+		}) {
+			return v.product
+		}
+	}
+	return "eclipse-temurin"
+}
+
+// packageVendor returns the distributor recorded for a package, currently the
+// implementor of JDK installations
+func packageVendor(p pkg.Package) string {
+	if release, ok := javaRelease(p); ok {
+		return release.Implementor
+	}
+	return ""
+}
@@ -0,0 +1,207 @@
+package scanning
+
+import (
+	"testing"
+
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// TestMavenCoordinates tests reading groupId:artifactId from PURLs and pom.properties
+func TestMavenCoordinates(t *testing.T) {
+	tests := []struct {
+		name         string
+		pkg          pkg.Package
+		wantGroup    string
+		wantArtifact string
+	}{
+		{
+			name:         "maven purl",
+			pkg:          pkg.Package{Name: "spring-core", PURL: "pkg:maven/org.springframework/spring-core@5.3.20"},
+			wantGroup:    "org.springframework",
+			wantArtifact: "spring-core",
+		},
+		{
+			name:         "maven purl with qualifiers",
+			pkg:          pkg.Package{Name: "log4j-core", PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1?type=jar"},
+			wantGroup:    "org.apache.logging.log4j",
+			wantArtifact: "log4j-core",
+		},
+		{
+			name: "pom properties",
+			pkg: pkg.Package{Name: "tomcat-embed-core", Metadata: pkg.JavaArchive{
+				PomProperties: &pkg.JavaPomProperties{GroupID: "org.apache.tomcat.embed", ArtifactID: "tomcat-embed-core"},
+			}},
+			wantGroup:    "org.apache.tomcat.embed",
+			wantArtifact: "tomcat-embed-core",
+		},
+		{
+			name: "no coordinates",
+			pkg:  pkg.Package{Name: "app", Metadata: pkg.JavaArchive{}},
+		},
+		{
+			name: "not a maven purl",
+			pkg:  pkg.Package{Name: "express", PURL: "pkg:npm/express@4.18.2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group, artifact := mavenCoordinates(tt.pkg)
+			if group != tt.wantGroup || artifact != tt.wantArtifact {
+				t.Errorf("mavenCoordinates() = %q:%q, want %q:%q", group, artifact, tt.wantGroup, tt.wantArtifact)
+			}
+		})
+	}
+}
+
+// TestFindMavenFramework tests matching Maven coordinates to frameworks
+func TestFindMavenFramework(t *testing.T) {
+	tests := []struct {
+		group    string
+		artifact string
+		product  string
+	}{
+		{"org.springframework.boot", "spring-boot-autoconfigure", "spring-boot"},
+		{"org.springframework.boot", "spring-boot", "spring-boot"},
+		{"org.springframework", "spring-core", "spring-framework"},
+		{"org.springframework", "spring-webmvc", "spring-framework"},
+		{"org.hibernate.orm", "hibernate-core", "hibernate-orm"},
+		{"org.hibernate", "hibernate-core", "hibernate-orm"},
+		{"org.apache.tomcat.embed", "tomcat-embed-core", "tomcat"},
+		{"org.apache.logging.log4j", "log4j-core", "log4j"},
+		{"log4j", "log4j", "log4j"},
+		{"com.fasterxml.jackson.core", "jackson-databind", "jackson"},
+
+		// Same artifact name in another group
+		{"com.example.fork", "spring-core", ""},
+
+		// Artifacts released independently of the framework
+		{"org.hibernate", "hibernate-commons-annotations", ""},
+		{"org.hibernate", "hibernate-validator", ""},
+		{"org.hibernate", "hibernate-search-orm", ""},
+		{"org.apache.logging.log4j", "log4j-api-kotlin", ""},
+		{"org.apache.logging.log4j", "log4j-api-scala_2.13", ""},
+		{"org.springframework.security", "spring-security-core", ""},
+		{"", "spring-core", ""},
+	}
+
+	for _, tt := range tests {
+		got := ""
+		if f := findMavenFramework(tt.group, tt.artifact); f != nil {
+			got = f.products[0]
+		}
+		if got != tt.product {
+			t.Errorf("findMavenFramework(%q, %q) = %q, want %q", tt.group, tt.artifact, got, tt.product)
+		}
+	}
+}
+
+// TestJavaVendorProduct tests mapping JDK release files to distributions
+func TestJavaVendorProduct(t *testing.T) {
+	tests := []struct {
+		name    string
+		release pkg.JavaVMRelease
+		want    string
+	}{
+		{"temurin", pkg.JavaVMRelease{Implementor: "Eclipse Adoptium"}, "eclipse-temurin"},
+		{"adoptopenjdk", pkg.JavaVMRelease{Implementor: "AdoptOpenJDK"}, "eclipse-temurin"},
+		{"corretto", pkg.JavaVMRelease{Implementor: "Amazon.com Inc."}, "amazon-corretto"},
+		{"zulu", pkg.JavaVMRelease{Implementor: "Azul Systems, Inc."}, "azul-zulu"},
+		{"oracle jdk", pkg.JavaVMRelease{Implementor: "Oracle Corporation", JavaRuntimeVersion: "17.0.9+11-LTS-201"}, "oracle-jdk"},
+		{"oracle jdk by implementor version", pkg.JavaVMRelease{Implementor: "Oracle Corporation", ImplementorVersion: "Oracle JDK"}, "oracle-jdk"},
+		{"openjdk build from oracle", pkg.JavaVMRelease{Implementor: "Oracle Corporation", JavaRuntimeVersion: "21.0.1+12-29"}, "eclipse-temurin"},
+		{"oracle without markers", pkg.JavaVMRelease{Implementor: "Oracle Corporation"}, "eclipse-temurin"},
+		{"red hat", pkg.JavaVMRelease{Implementor: "Red Hat, Inc."}, "redhat-build-of-openjdk"},
+		{"microsoft", pkg.JavaVMRelease{Implementor: "Microsoft"}, "microsoft-build-of-openjdk"},
+		{"liberica", pkg.JavaVMRelease{Implementor: "BellSoft"}, "bellsoft-liberica"},
+		{"sapmachine", pkg.JavaVMRelease{Implementor: "SAP SE"}, "sapmachine"},
+		{"semeru", pkg.JavaVMRelease{Implementor: "IBM Corporation"}, "ibm-semeru-runtime"},
+		{"debian", pkg.JavaVMRelease{Implementor: "Debian"}, "eclipse-temurin"},
+		{"no implementor", pkg.JavaVMRelease{}, "eclipse-temurin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := javaVendorProduct(tt.release); got != tt.want {
+				t.Errorf("javaVendorProduct(%+v) = %q, want %q", tt.release, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRuntimeProductJVM tests that JDK installations are matched by vendor
+func TestRuntimeProductJVM(t *testing.T) {
+	jvm := func(name, version, implementor string) pkg.Package {
+		return pkg.Package{
+			Name:     name,
+			Version:  version,
+			Type:     pkg.BinaryPkg,
+			Metadata: pkg.JavaVMInstallation{Release: pkg.JavaVMRelease{Implementor: implementor, JavaRuntimeVersion: version}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		pkg         pkg.Package
+		wantProduct string
+		wantVersion string
+	}{
+		{"temurin", jvm("jdk", "21.0.4+7-LTS", "Eclipse Adoptium"), "eclipse-temurin", "21.0.4+7-LTS"},
+		{"corretto legacy version", jvm("jre", "1.8.0_392-b08", "Amazon.com Inc."), "amazon-corretto", "8.0.392"},
+		{"zulu", jvm("zulu", "17.0.9+8-LTS", "Azul Systems, Inc."), "azul-zulu", "17.0.9+8-LTS"},
+		{"oracle", jvm("jdk", "17.0.9+11-LTS-201", "Oracle Corporation"), "oracle-jdk", "17.0.9+11-LTS-201"},
+		{"openjdk", jvm("openjdk", "21.0.1+12-29", "Oracle Corporation"), "eclipse-temurin", "21.0.1+12-29"},
+		{"graalvm", jvm("graalvm", "21.0.1+12-jvmci-23.1-b19", "Oracle Corporation"), "graalvm", "21.0.1+12-jvmci-23.1-b19"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product, version, ok := runtimeProduct(tt.pkg)
+			if !ok || product != tt.wantProduct || version != tt.wantVersion {
+				t.Errorf("runtimeProduct() = %q, %q, %v, want %q, %q", product, version, ok, tt.wantProduct, tt.wantVersion)
+			}
+			if got := newComponentResult(tt.pkg).Vendor; got != tt.pkg.Metadata.(pkg.JavaVMInstallation).Release.Implementor {
+				t.Errorf("Vendor = %q", got)
+			}
+		})
+	}
+}
+
+// TestMatchStepsMaven tests that Java archives are matched on their full coordinates
+func TestMatchStepsMaven(t *testing.T) {
+	scanner := &Scanner{config: DefaultScannerConfig()}
+
+	steps := scanner.matchSteps(pkg.Package{
+		Name:    "spring-core",
+		Version: "5.3.20",
+		Type:    pkg.JavaPkg,
+		PURL:    "pkg:maven/org.springframework/spring-core@5.3.20",
+	})
+
+	var maven, prefix *chainStep
+	for i := range steps {
+		switch {
+		case steps[i].Method == MatchMaven:
+			maven = &steps[i]
+		case steps[i].Method == MatchPURLPrefix && prefix == nil:
+			prefix = &steps[i]
+		}
+	}
+	if maven == nil || maven.Lookup == nil || maven.Query != "org.springframework:spring-core" {
+		t.Errorf("maven step = %+v, want an active org.springframework:spring-core lookup", maven)
+	}
+	if prefix == nil || prefix.Query != "pkg:maven/org.springframework/spring-core" {
+		t.Errorf("purl prefix step = %+v, want a lookup scoped to the group", prefix)
+	}
+
+	steps = scanner.matchSteps(pkg.Package{
+		Name: "commons-lang3",
+		Type: pkg.JavaPkg,
+		PURL: "pkg:maven/org.apache.commons/commons-lang3@3.12.0",
+	})
+	for _, step := range steps {
+		if step.Method == MatchMaven && step.Lookup != nil {
+			t.Errorf("unknown framework should skip the maven step, got %q", step.Query)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package scanning
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+// LayerSource tells whether a component comes from the base image or from a layer added
+// on top of it
+type LayerSource string
+
+const (
+	LayerSourceBase        LayerSource = "base"
+	LayerSourceApplication LayerSource = "application"
+)
+
+// imageLayer is a layer of the scanned image and the history entry that created it
+type imageLayer struct {
+	digest      string
+	instruction string
+	source      LayerSource
+}
+
+// imageLayers holds the layers of the scanned image in order, indexed by digest
+type imageLayers struct {
+	layers []imageLayer
+	index  map[string]int
+}
+
+// imageHistory is the part of an OCI image config describing how its layers were built
+type imageHistory struct {
+	History []struct {
+		CreatedBy  string `json:"created_by"`
+		EmptyLayer bool   `json:"empty_layer"`
+	} `json:"history"`
+}
+
+// buildArgsPrefix matches the "|2 VERSION=1.0 TARGET=x " prefix the docker builder
+// records before RUN commands that use build arguments
+var buildArgsPrefix = regexp.MustCompile(`^\|\d+ (?:\S+=\S* )*`)
+
+// newImageLayers reads the layers and build history of an image source. Sources that
+// are not images (or images without layers) return nil.
+func newImageLayers(description source.Description) *imageLayers {
+	var metadata source.ImageMetadata
+	switch m := description.Metadata.(type) {
+	case source.ImageMetadata:
+		metadata = m
+	case *source.ImageMetadata:
+		metadata = *m
+	default:
+		return nil
+	}
+	if len(metadata.Layers) == 0 {
+		return nil
+	}
+
+	l := &imageLayers{
+		layers: make([]imageLayer, len(metadata.Layers)),
+		index:  make(map[string]int, len(metadata.Layers)),
+	}
+	for i, layer := range metadata.Layers {
+		l.layers[i] = imageLayer{digest: layer.Digest}
+		l.index[layer.Digest] = i
+	}
+
+	var config imageHistory
+	if err := json.Unmarshal(metadata.RawConfig, &config); err != nil {
+		return l
+	}
+
+	// History entries that created a layer line up with the layers of the image
+	var instructions []string
+	base, ended := -1, false
+	for _, h := range config.History {
+		instruction := dockerInstruction(h.CreatedBy)
+		if h.EmptyLayer {
+			ended = ended || isImageEndInstruction(instruction)
+			continue
+		}
+		// The CMD or ENTRYPOINT a base image ends with marks where the image built
+		// on top of it starts, once further layers follow
+		if ended {
+			base, ended = len(instructions), false
+		}
+		instructions = append(instructions, instruction)
+	}
+	if len(instructions) != len(l.layers) {
+		return l
+	}
+
+	for i := range l.layers {
+		l.layers[i].instruction = instructions[i]
+		if base > 0 {
+			if i < base {
+				l.layers[i].source = LayerSourceBase
+			} else {
+				l.layers[i].source = LayerSourceApplication
+			}
+		}
+	}
+	return l
+}
+
+// dockerInstruction converts the created_by field of a history entry into the Dockerfile
+// instruction that produced it
+func dockerInstruction(createdBy string) string {
+	instruction := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(createdBy), "# buildkit"))
+
+	// The legacy builder records non-RUN instructions as "/bin/sh -c #(nop) CMD [...]"
+	// and RUN instructions as the bare shell command
+	if rest, ok := strings.CutPrefix(instruction, "/bin/sh -c #(nop)"); ok {
+		return strings.TrimSpace(rest)
+	}
+	command, isRun := strings.CutPrefix(instruction, "RUN ")
+	command = buildArgsPrefix.ReplaceAllString(command, "")
+	if isRun || strings.HasPrefix(command, "/bin/sh -c ") {
+		return "RUN " + command
+	}
+	return instruction
+}
+
+// isImageEndInstruction reports whether an instruction is the CMD or ENTRYPOINT a base
+// image typically ends with
+func isImageEndInstruction(instruction string) bool {
+	return strings.HasPrefix(instruction, "CMD ") || strings.HasPrefix(instruction, "ENTRYPOINT ")
+}
+
+// attribute records the layer that introduced a package: the earliest layer holding any
+// of its files. Package databases are rewritten by every layer that installs packages,
+// but the per-package files syft records (dpkg file lists, copyright files) stay in the
+// layer that installed the package.
+func (l *imageLayers) attribute(result *ComponentResult, p pkg.Package) {
+	if l == nil {
+		return
+	}
+
+	earliest := -1
+	for _, location := range p.Locations.ToSlice() {
+		i, ok := l.index[location.FileSystemID]
+		if ok && (earliest < 0 || i < earliest) {
+			earliest = i
+		}
+	}
+	if earliest < 0 {
+		return
+	}
+
+	layer := l.layers[earliest]
+	result.LayerDigest = layer.digest
+	result.LayerInstruction = layer.instruction
+	result.LayerSource = layer.source
+}
+
+// packageLocations returns the sorted, distinct paths at which a package was found
+func packageLocations(p pkg.Package) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, location := range p.Locations.ToSlice() {
+		if location.RealPath == "" || seen[location.RealPath] {
+			continue
+		}
+		seen[location.RealPath] = true
+		paths = append(paths, location.RealPath)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,150 @@
+package scanning
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+// layerLocation builds a location of a file in an image layer
+func layerLocation(path, layer string) file.Location {
+	return file.NewLocationFromCoordinates(file.NewCoordinates(path, layer))
+}
+
+// testImage describes a python image built on debian with one application layer on top
+func testImage() source.Description {
+	return source.Description{Metadata: source.ImageMetadata{
+		Layers: []source.LayerMetadata{
+			{Digest: "sha256:debian"},
+			{Digest: "sha256:python"},
+			{Digest: "sha256:app"},
+		},
+		RawConfig: []byte(`{"history": [
+			{"created_by": "/bin/sh -c #(nop) ADD file:1234 in / "},
+			{"created_by": "/bin/sh -c #(nop)  CMD [\"bash\"]", "empty_layer": true},
+			{"created_by": "/bin/sh -c apt-get update && apt-get install -y python3"},
+			{"created_by": "CMD [\"python3\"]", "empty_layer": true},
+			{"created_by": "RUN |1 REQS=prod /bin/sh -c pip install -r requirements.txt # buildkit"}
+		]}`),
+	}}
+}
+
+// TestDockerInstruction tests converting history entries to Dockerfile instructions
+func TestDockerInstruction(t *testing.T) {
+	tests := []struct {
+		createdBy string
+		want      string
+	}{
+		{"/bin/sh -c #(nop) ADD file:1234 in / ", "ADD file:1234 in /"},
+		{"/bin/sh -c #(nop)  CMD [\"bash\"]", `CMD ["bash"]`},
+		{"/bin/sh -c apt-get update", "RUN /bin/sh -c apt-get update"},
+		{"|2 A=1 B=2 /bin/sh -c make", "RUN /bin/sh -c make"},
+		{"RUN /bin/sh -c apk add curl # buildkit", "RUN /bin/sh -c apk add curl"},
+		{"COPY app /app # buildkit", "COPY app /app"},
+	}
+
+	for _, tt := range tests {
+		if got := dockerInstruction(tt.createdBy); got != tt.want {
+			t.Errorf("dockerInstruction(%q) = %q, want %q", tt.createdBy, got, tt.want)
+		}
+	}
+}
+
+// TestNewImageLayers tests reading layers and classifying them as base or application
+func TestNewImageLayers(t *testing.T) {
+	layers := newImageLayers(testImage())
+	if layers == nil {
+		t.Fatal("newImageLayers() = nil")
+	}
+
+	want := []imageLayer{
+		{"sha256:debian", "ADD file:1234 in /", LayerSourceBase},
+		{"sha256:python", "RUN /bin/sh -c apt-get update && apt-get install -y python3", LayerSourceBase},
+		{"sha256:app", "RUN /bin/sh -c pip install -r requirements.txt", LayerSourceApplication},
+	}
+	if !slices.Equal(layers.layers, want) {
+		t.Errorf("layers = %+v, want %+v", layers.layers, want)
+	}
+
+	if newImageLayers(source.Description{Metadata: source.DirectoryMetadata{Path: "/src"}}) != nil {
+		t.Error("newImageLayers() returned layers for a directory source")
+	}
+}
+
+// TestNewImageLayersWithoutBoundary tests that layers stay unclassified when the base image can't be told apart
+func TestNewImageLayersWithoutBoundary(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"base image itself", `{"history": [{"created_by": "ADD rootfs /"}, {"created_by": "CMD [\"bash\"]", "empty_layer": true}]}`},
+		{"history mismatch", `{"history": [{"created_by": "ADD rootfs /"}]}`},
+		{"no config", ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layers := newImageLayers(source.Description{Metadata: source.ImageMetadata{
+				Layers:    []source.LayerMetadata{{Digest: "sha256:a"}, {Digest: "sha256:b"}},
+				RawConfig: []byte(tt.config),
+			}})
+			for _, layer := range layers.layers {
+				if layer.source != "" {
+					t.Errorf("layer %s classified as %s", layer.digest, layer.source)
+				}
+			}
+		})
+	}
+}
+
+// TestImageLayersAttribute tests attributing packages to the earliest layer holding their files
+func TestImageLayersAttribute(t *testing.T) {
+	layers := newImageLayers(testImage())
+
+	// dpkg's status file is rewritten by the app layer, but the package's file list
+	// stays in the layer that installed it
+	p := pkg.Package{Name: "python3.11", Locations: file.NewLocationSet(
+		layerLocation("/var/lib/dpkg/status", "sha256:app"),
+		layerLocation("/var/lib/dpkg/info/python3.11.list", "sha256:python"),
+	)}
+	var result ComponentResult
+	layers.attribute(&result, p)
+
+	if result.LayerDigest != "sha256:python" || result.LayerSource != LayerSourceBase {
+		t.Errorf("attribute() = %s (%s), want sha256:python (base)", result.LayerDigest, result.LayerSource)
+	}
+	if result.LayerInstruction == "" {
+		t.Error("attribute() recorded no instruction")
+	}
+
+	app := pkg.Package{Name: "django", Locations: file.NewLocationSet(layerLocation("/usr/lib/python3/site-packages/Django.dist-info/METADATA", "sha256:app"))}
+	result = ComponentResult{}
+	layers.attribute(&result, app)
+	if result.LayerSource != LayerSourceApplication {
+		t.Errorf("attribute() source = %s, want %s", result.LayerSource, LayerSourceApplication)
+	}
+
+	var none *imageLayers
+	result = ComponentResult{}
+	none.attribute(&result, app)
+	if result.LayerDigest != "" {
+		t.Error("attribute() on nil layers recorded a layer")
+	}
+}
+
+// TestPackageLocations tests that component locations are sorted and distinct
+func TestPackageLocations(t *testing.T) {
+	p := pkg.Package{Locations: file.NewLocationSet(
+		layerLocation("/var/lib/dpkg/status", "sha256:app"),
+		layerLocation("/var/lib/dpkg/status", "sha256:python"),
+		layerLocation("/usr/share/doc/curl/copyright", "sha256:python"),
+	)}
+
+	want := []string{"/usr/share/doc/curl/copyright", "/var/lib/dpkg/status"}
+	if got := packageLocations(p); !slices.Equal(got, want) {
+		t.Errorf("packageLocations() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,222 @@
+package scanning
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/j0356/eol-scanner/core/db"
+	"gopkg.in/yaml.v3"
+)
+
+// MappingConfig holds user-supplied package-to-product mappings. It is loaded from a
+// YAML (or JSON) file and consulted before the built-in matching strategies.
+//
+//	mappings:
+//	  - purl: "pkg:generic/acme-python*"
+//	    product: python
+//	    version_regex: "python-([0-9.]+)"
+//	  - name: "acme-jre"
+//	    type: binary
+//	    product: eclipse-temurin
+//	  - cpe: "cpe:2.3:a:acme:runtime"
+//	    product: nodejs
+//	distros:
+//	  mycorp-linux: debian
+type MappingConfig struct {
+	Mappings []PackageMapping  `yaml:"mappings" json:"mappings"`
+	Distros  map[string]string `yaml:"distros" json:"distros"`
+}
+
+// PackageMapping maps packages to an endoflife.date product. A mapping applies when all
+// of its set selectors match: PURL and name are glob patterns ("*" and "?", where "*"
+// does not cross a "/"), CPE is a prefix and type is the Syft package type.
+type PackageMapping struct {
+	PURL         string `yaml:"purl,omitempty" json:"purl,omitempty"`
+	Name         string `yaml:"name,omitempty" json:"name,omitempty"`
+	Type         string `yaml:"type,omitempty" json:"type,omitempty"`
+	CPE          string `yaml:"cpe,omitempty" json:"cpe,omitempty"`
+	Product      string `yaml:"product" json:"product"`
+	VersionRegex string `yaml:"version_regex,omitempty" json:"version_regex,omitempty"`
+
+	versionRegex *regexp.Regexp
+}
+
+// LoadMappings reads and validates a mapping file
+func LoadMappings(filePath string) (*MappingConfig, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping file: %w", err)
+	}
+
+	var config MappingConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping file %s: %w", filePath, err)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid mapping file %s: %w", filePath, err)
+	}
+
+	return &config, nil
+}
+
+// validate checks the mappings and compiles their version regexes
+func (c *MappingConfig) validate() error {
+	for i := range c.Mappings {
+		m := &c.Mappings[i]
+		if m.Product == "" {
+			return fmt.Errorf("mapping %d: product is required", i+1)
+		}
+		if m.PURL == "" && m.Name == "" && m.CPE == "" {
+			return fmt.Errorf("mapping %d: one of purl, name or cpe is required", i+1)
+		}
+		for _, pattern := range []string{m.PURL, m.Name} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("mapping %d: invalid pattern %q: %w", i+1, pattern, err)
+			}
+		}
+		if m.VersionRegex != "" {
+			re, err := regexp.Compile(m.VersionRegex)
+			if err != nil {
+				return fmt.Errorf("mapping %d: invalid version_regex: %w", i+1, err)
+			}
+			m.versionRegex = re
+		}
+	}
+	return nil
+}
+
+// checkProducts reports the first mapping or distro whose product is not in the
+// database, which would otherwise leave its packages to the built-in matchers without
+// a word
+func (c *MappingConfig) checkProducts(products db.ProductLookup) error {
+	if c == nil {
+		return nil
+	}
+	for i, m := range c.Mappings {
+		if err := checkProduct(products, m.Product); err != nil {
+			return fmt.Errorf("mapping %d: %w", i+1, err)
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(c.Distros)) {
+		if err := checkProduct(products, c.Distros[id]); err != nil {
+			return fmt.Errorf("distro %q: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// checkProduct returns an error if a product is not in the database
+func checkProduct(products db.ProductLookup, name string) error {
+	product, _, err := products.LookupByName(name, "")
+	if err != nil {
+		return fmt.Errorf("failed to look up product %q: %w", name, err)
+	}
+	if product == nil {
+		return fmt.Errorf("unknown product %q", name)
+	}
+	return nil
+}
+
+// findMapping returns the first mapping that applies to a package
+func (c *MappingConfig) findMapping(p pkg.Package) *PackageMapping {
+	if c == nil {
+		return nil
+	}
+	for i := range c.Mappings {
+		if c.Mappings[i].matches(p) {
+			return &c.Mappings[i]
+		}
+	}
+	return nil
+}
+
+// distroProduct returns the product a distro ID is mapped to, if any
+func (c *MappingConfig) distroProduct(distroID string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	for id, product := range c.Distros {
+		if strings.EqualFold(id, distroID) {
+			return product, true
+		}
+	}
+	return "", false
+}
+
+// matches reports whether every selector set on the mapping matches the package
+func (m *PackageMapping) matches(p pkg.Package) bool {
+	if m.Type != "" && !strings.EqualFold(m.Type, string(p.Type)) {
+		return false
+	}
+	if m.Name != "" && !globMatch(m.Name, p.Name) {
+		return false
+	}
+	if m.PURL != "" && (p.PURL == "" || !globMatch(m.PURL, p.PURL)) {
+		return false
+	}
+	if m.CPE != "" && !hasCPEPrefix(p, m.CPE) {
+		return false
+	}
+	return true
+}
+
+// describe returns the selectors of the mapping, used as the match identifier
+func (m *PackageMapping) describe() string {
+	var parts []string
+	if m.PURL != "" {
+		parts = append(parts, "purl="+m.PURL)
+	}
+	if m.Name != "" {
+		parts = append(parts, "name="+m.Name)
+	}
+	if m.Type != "" {
+		parts = append(parts, "type="+m.Type)
+	}
+	if m.CPE != "" {
+		parts = append(parts, "cpe="+m.CPE)
+	}
+	return strings.Join(parts, ",")
+}
+
+// extractVersion applies the version regex to the package version, then to the
+// package name. The first capture group is used, or the whole match if there is none.
+// Without a regex, or when it does not match, the package version is returned.
+func (m *PackageMapping) extractVersion(p pkg.Package) string {
+	if m.versionRegex == nil {
+		return p.Version
+	}
+	for _, source := range []string{p.Version, p.Name} {
+		match := m.versionRegex.FindStringSubmatch(source)
+		if match == nil {
+			continue
+		}
+		if len(match) > 1 {
+			return match[1]
+		}
+		return match[0]
+	}
+	return p.Version
+}
+
+// globMatch matches a case-insensitive glob pattern against a whole string
+func globMatch(pattern, s string) bool {
+	ok, err := path.Match(strings.ToLower(pattern), strings.ToLower(s))
+	return err == nil && ok
+}
+
+// hasCPEPrefix reports whether any CPE of the package starts with prefix
+func hasCPEPrefix(p pkg.Package, prefix string) bool {
+	for _, c := range p.CPEs {
+		if strings.HasPrefix(strings.ToLower(c.Attributes.String()), strings.ToLower(prefix)) {
+			return true
+		}
+	}
+	return false
+}
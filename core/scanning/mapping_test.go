@@ -0,0 +1,274 @@
+package scanning
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anchore/syft/syft/cpe"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/j0356/eol-scanner/core/db"
+)
+
+// writeMappingFile writes a mapping file into a temporary directory
+func writeMappingFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write mapping file: %v", err)
+	}
+	return path
+}
+
+// TestLoadMappings tests loading YAML and JSON mapping files
+func TestLoadMappings(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "yaml",
+			file: "mappings.yaml",
+			content: `mappings:
+  - purl: "pkg:generic/acme-python*"
+    product: python
+    version_regex: "python-([0-9.]+)"
+distros:
+  mycorp-linux: debian
+`,
+		},
+		{
+			name: "json",
+			file: "mappings.json",
+			content: `{
+  "mappings": [
+    {"purl": "pkg:generic/acme-python*", "product": "python", "version_regex": "python-([0-9.]+)"}
+  ],
+  "distros": {"mycorp-linux": "debian"}
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := LoadMappings(writeMappingFile(t, tt.file, tt.content))
+			if err != nil {
+				t.Fatalf("LoadMappings() error = %v", err)
+			}
+			if len(config.Mappings) != 1 {
+				t.Fatalf("len(Mappings) = %d, want 1", len(config.Mappings))
+			}
+			m := config.Mappings[0]
+			if m.Product != "python" || m.PURL != "pkg:generic/acme-python*" {
+				t.Errorf("mapping = %+v", m)
+			}
+			if m.versionRegex == nil {
+				t.Error("version regex was not compiled")
+			}
+			if product, ok := config.distroProduct("mycorp-linux"); !ok || product != "debian" {
+				t.Errorf("distroProduct() = %q, %v, want debian, true", product, ok)
+			}
+		})
+	}
+}
+
+// TestLoadMappingsInvalid tests that invalid mapping files are rejected
+func TestLoadMappingsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"missing product", "mappings:\n  - name: acme\n", "product is required"},
+		{"missing selector", "mappings:\n  - type: binary\n    product: go\n", "one of purl, name or cpe is required"},
+		{"bad pattern", "mappings:\n  - name: \"acme[\"\n    product: go\n", "invalid pattern"},
+		{"bad regex", "mappings:\n  - name: acme\n    product: go\n    version_regex: \"(\"\n", "invalid version_regex"},
+		{"malformed", "mappings: [\n", "failed to parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadMappings(writeMappingFile(t, "mappings.yaml", tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadMappings() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := LoadMappings(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("LoadMappings() on a missing file returned no error")
+	}
+}
+
+// TestFindMapping tests matching packages against mapping selectors
+func TestFindMapping(t *testing.T) {
+	config := &MappingConfig{Mappings: []PackageMapping{
+		{PURL: "pkg:generic/acme-python*", Product: "python"},
+		{Name: "acme-jre", Type: "binary", Product: "eclipse-temurin"},
+		{CPE: "cpe:2.3:a:acme:runtime", Product: "nodejs"},
+		{Name: "acme-*", Product: "catch-all"},
+	}}
+	if err := config.validate(); err != nil {
+		t.Fatalf("validate() error = %v", err)
+	}
+
+	runtimeCPE := cpe.Must("cpe:2.3:a:acme:runtime:18.19.0:*:*:*:*:*:*:*", cpe.GeneratedSource)
+
+	tests := []struct {
+		name    string
+		pkg     pkg.Package
+		product string
+	}{
+		{"purl glob", pkg.Package{Name: "acme-python3", PURL: "pkg:generic/acme-python3@3.11.4"}, "python"},
+		{"purl glob is case-insensitive", pkg.Package{Name: "x", PURL: "pkg:generic/ACME-Python@3.11"}, "python"},
+		{"name and type", pkg.Package{Name: "acme-jre", Type: pkg.BinaryPkg}, "eclipse-temurin"},
+		{"type mismatch falls through", pkg.Package{Name: "acme-jre", Type: pkg.DebPkg}, "catch-all"},
+		{"cpe prefix", pkg.Package{Name: "rt", CPEs: []cpe.CPE{runtimeCPE}}, "nodejs"},
+		{"first mapping wins", pkg.Package{Name: "acme-tool", PURL: "pkg:generic/acme-python-tool@1"}, "python"},
+		{"no match", pkg.Package{Name: "openssl", Type: pkg.DebPkg}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if m := config.findMapping(tt.pkg); m != nil {
+				got = m.Product
+			}
+			if got != tt.product {
+				t.Errorf("findMapping() = %q, want %q", got, tt.product)
+			}
+		})
+	}
+
+	var empty *MappingConfig
+	if empty.findMapping(pkg.Package{Name: "acme-jre"}) != nil {
+		t.Error("findMapping() on a nil config returned a mapping")
+	}
+}
+
+// TestExtractVersion tests deriving the cycle version from a mapping's version regex
+func TestExtractVersion(t *testing.T) {
+	config := &MappingConfig{Mappings: []PackageMapping{
+		{Name: "a", Product: "python", VersionRegex: `python-([0-9.]+)`},
+		{Name: "b", Product: "go", VersionRegex: `[0-9]+\.[0-9]+`},
+		{Name: "c", Product: "nodejs"},
+	}}
+	if err := config.validate(); err != nil {
+		t.Fatalf("validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		mapping int
+		pkg     pkg.Package
+		want    string
+	}{
+		{"capture group from version", 0, pkg.Package{Name: "acme", Version: "python-3.11.4-r2"}, "3.11.4"},
+		{"capture group from name", 0, pkg.Package{Name: "acme-python-3.9", Version: "2024.01"}, "3.9"},
+		{"whole match without group", 1, pkg.Package{Name: "acme", Version: "toolchain 1.21.5"}, "1.21"},
+		{"no match keeps version", 0, pkg.Package{Name: "acme", Version: "7.0"}, "7.0"},
+		{"no regex keeps version", 2, pkg.Package{Name: "acme", Version: "18.19.0"}, "18.19.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := config.Mappings[tt.mapping].extractVersion(tt.pkg); got != tt.want {
+				t.Errorf("extractVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDistroProduct tests distro overrides, which are matched case-insensitively
+func TestDistroProduct(t *testing.T) {
+	config := &MappingConfig{Distros: map[string]string{"MyCorp-Linux": "rhel"}}
+
+	if product, ok := config.distroProduct("mycorp-linux"); !ok || product != "rhel" {
+		t.Errorf("distroProduct() = %q, %v, want rhel, true", product, ok)
+	}
+	if _, ok := config.distroProduct("debian"); ok {
+		t.Error("distroProduct() matched an unmapped distro")
+	}
+
+	var empty *MappingConfig
+	if _, ok := empty.distroProduct("debian"); ok {
+		t.Error("distroProduct() on a nil config matched")
+	}
+}
+
+// TestMatchStepsUserMapping tests that user mappings are consulted before built-in matching
+func TestMatchStepsUserMapping(t *testing.T) {
+	config := &MappingConfig{Mappings: []PackageMapping{
+		{Name: "acme-python", Type: "deb", Product: "python", VersionRegex: `^([0-9]+\.[0-9]+)`},
+	}}
+	if err := config.validate(); err != nil {
+		t.Fatalf("validate() error = %v", err)
+	}
+	scanner := &Scanner{config: DefaultScannerConfig(), mappings: config}
+
+	steps := scanner.matchSteps(pkg.Package{
+		Name:    "acme-python",
+		Version: "3.11.4-1",
+		Type:    pkg.DebPkg,
+		PURL:    "pkg:deb/debian/acme-python@3.11.4-1",
+	})
+	if steps[0].Method != MatchUserMapping || steps[0].Lookup == nil {
+		t.Fatalf("first step = %s (skipped %v), want an active user mapping step", steps[0].Method, steps[0].Lookup == nil)
+	}
+	if steps[0].Query != "name=acme-python,type=deb" {
+		t.Errorf("query = %q, want %q", steps[0].Query, "name=acme-python,type=deb")
+	}
+	if steps[0].Version != "3.11" {
+		t.Errorf("version = %q, want 3.11", steps[0].Version)
+	}
+
+	steps = scanner.matchSteps(pkg.Package{Name: "nginx", Type: pkg.DebPkg})
+	if steps[0].Method != MatchUserMapping || steps[0].Lookup != nil {
+		t.Errorf("first step = %s (skipped %v), want a skipped user mapping step", steps[0].Method, steps[0].Lookup == nil)
+	}
+
+	info := newMatchInfo(MatchUserMapping, &db.LookupMatch{Method: db.MatchMethodName, Identifier: "python"}, pkg.Package{Name: "acme-python"})
+	if info.Confidence != ConfidenceHigh {
+		t.Errorf("user mapping confidence = %s, want %s", info.Confidence, ConfidenceHigh)
+	}
+}
+
+// TestCheckProducts tests that mappings to products missing from the database are
+// reported instead of silently falling through to the built-in matchers
+func TestCheckProducts(t *testing.T) {
+	scanner := newTestDBScanner(t, db.ProductData{Name: "python", Category: "lang"})
+
+	valid := &MappingConfig{Mappings: []PackageMapping{{Name: "acme-python", Product: "python"}}}
+	if err := valid.checkProducts(scanner.lookups()); err != nil {
+		t.Errorf("checkProducts() error = %v", err)
+	}
+
+	var none *MappingConfig
+	if err := none.checkProducts(scanner.lookups()); err != nil {
+		t.Errorf("checkProducts() on a nil config error = %v", err)
+	}
+
+	typo := &MappingConfig{Mappings: []PackageMapping{
+		{Name: "acme-python", Product: "python"},
+		{Name: "acme-node", Product: "nodjs"},
+	}}
+	err := typo.checkProducts(scanner.lookups())
+	if err == nil || !strings.Contains(err.Error(), `mapping 2: unknown product "nodjs"`) {
+		t.Errorf("checkProducts() error = %v, want an unknown product error", err)
+	}
+
+	distroTypo := &MappingConfig{Distros: map[string]string{"acme": "pyhton", "acmeos": "python"}}
+	err = distroTypo.checkProducts(scanner.lookups())
+	if err == nil || !strings.Contains(err.Error(), `distro "acme": unknown product "pyhton"`) {
+		t.Errorf("checkProducts() error = %v, want an unknown distro product error", err)
+	}
+
+	// Explain records why the mapping did not match
+	scanner.mappings = typo
+	explanation := explainPackage(t, scanner, pkg.Package{Name: "acme-node", Type: pkg.BinaryPkg})
+	if len(explanation.Attempts) == 0 || explanation.Attempts[0].Reason != `mapped product "nodjs" is not in the database` {
+		t.Errorf("first attempt = %+v, want the missing mapped product", explanation.Attempts)
+	}
+}
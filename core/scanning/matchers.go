@@ -0,0 +1,423 @@
+package scanning
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/j0356/eol-scanner/core/db"
+)
+
+// Matcher is one link of the chain that finds the product of a package. The scanner
+// asks every matcher in chain order and the first one that finds a product wins;
+// `explain` asks them all.
+type Matcher interface {
+	// Name identifies the matcher, as in ScannerConfig.DisabledMatchers, and is the
+	// match method reported for its candidates
+	Name() string
+	// Match looks up the product of a package, returning nil when the matcher does
+	// not recognize the package
+	Match(p pkg.Package, products db.ProductLookup) (*MatchCandidate, error)
+}
+
+// MatchCandidate is the product a matcher found for a package, with its cycles
+type MatchCandidate struct {
+	Product    *db.Product
+	Cycles     []db.Cycle
+	Version    string          // Version to evaluate instead of the package version
+	Confidence MatchConfidence // Graded from the method and identifier when empty
+}
+
+// NewMatcher returns a Matcher built from a name and a match function
+func NewMatcher(name string, match func(p pkg.Package, products db.ProductLookup) (*MatchCandidate, error)) Matcher {
+	return funcMatcher{name: name, match: match}
+}
+
+// funcMatcher is a Matcher implemented by a function
+type funcMatcher struct {
+	name  string
+	match func(p pkg.Package, products db.ProductLookup) (*MatchCandidate, error)
+}
+
+func (m funcMatcher) Name() string { return m.name }
+
+func (m funcMatcher) Match(p pkg.Package, products db.ProductLookup) (*MatchCandidate, error) {
+	return m.match(p, products)
+}
+
+// matchEnv is what the built-in matchers look packages up in
+type matchEnv struct {
+	Products db.ProductLookup // Products, identifiers and cycles of the EOL database
+	Mappings *MappingConfig   // User mappings (nil when none are configured)
+}
+
+// matchStep is one lookup of a built-in matcher
+type matchStep struct {
+	Method  MatchMethod
+	Query   string
+	Skip    string // Why the step does not apply to the package (Lookup is nil)
+	Version string // Version to evaluate instead of the package version
+	Lookup  func() (*MatchCandidate, error)
+	Miss    string // Why Lookup found nothing, when more is known than that no identifier matched
+}
+
+// builtinMatcher is a built-in Matcher. It makes its lookups in steps, so that explain
+// can trace each of them.
+type builtinMatcher struct {
+	name  string
+	steps func(p pkg.Package, env *matchEnv) []matchStep
+}
+
+func (m builtinMatcher) Name() string { return m.name }
+
+// Match returns the candidate of the first step that finds a product, or the error of
+// the first lookup that fails. User mappings are only known to a Scanner, so
+// user_mapping matches nothing here.
+func (m builtinMatcher) Match(p pkg.Package, products db.ProductLookup) (*MatchCandidate, error) {
+	for _, step := range m.steps(p, &matchEnv{Products: products}) {
+		if step.Lookup == nil {
+			continue
+		}
+		candidate, err := step.Lookup()
+		if err != nil {
+			return nil, fmt.Errorf("%s lookup %q: %w", step.Method, step.Query, err)
+		}
+		if candidate == nil {
+			continue
+		}
+		if candidate.Version == "" {
+			candidate.Version = step.Version
+		}
+		return candidate, nil
+	}
+	return nil, nil
+}
+
+// DefaultMatchers returns the built-in matching chain: user mappings, exact PURL, known
+// runtime binaries, Maven frameworks, versioned distro package names, distro PURLs,
+// source package, ecosystem PURL prefix, generic PURL, CPEs and finally the package name
+func DefaultMatchers() []Matcher {
+	return []Matcher{
+		builtinMatcher{"user_mapping", userMappingSteps},
+		builtinMatcher{"exact_purl", exactPURLSteps},
+		builtinMatcher{"runtime", runtimeSteps},
+		builtinMatcher{"maven_coordinates", mavenSteps},
+		builtinMatcher{"versioned_name", versionedNameSteps},
+		builtinMatcher{"distro_purl", distroPURLSteps},
+		builtinMatcher{"source_package", sourcePackageSteps},
+		builtinMatcher{"purl_prefix", purlPrefixSteps},
+		builtinMatcher{"generic_purl", genericPURLSteps},
+		builtinMatcher{"cpe", cpeSteps},
+		builtinMatcher{"name", nameSteps},
+	}
+}
+
+// matcherChain builds the chain of a scanner configuration: its matchers, or the
+// built-in chain, without the disabled ones
+func matcherChain(config *ScannerConfig) ([]Matcher, error) {
+	matchers := config.Matchers
+	if matchers == nil {
+		matchers = DefaultMatchers()
+	}
+
+	var names []string
+	for _, m := range matchers {
+		names = append(names, m.Name())
+	}
+	for _, name := range config.DisabledMatchers {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("unknown matcher %q (available: %s)", name, strings.Join(names, ", "))
+		}
+	}
+
+	var chain []Matcher
+	for _, m := range matchers {
+		if !slices.Contains(config.DisabledMatchers, m.Name()) {
+			chain = append(chain, m)
+		}
+	}
+	return chain, nil
+}
+
+// chainStep is a step of the matching chain with the matcher it came from
+type chainStep struct {
+	matcher string
+	matchStep
+}
+
+// matchSteps builds the ordered lookup chain for a package from the scanner's matchers.
+// Built-in matchers contribute each of their lookups, other matchers one lookup.
+func (s *Scanner) matchSteps(p pkg.Package) []chainStep {
+	matchers := s.matchers
+	if matchers == nil {
+		matchers = DefaultMatchers()
+	}
+	env := &matchEnv{Products: scannerProducts{s}, Mappings: s.mappings}
+
+	var steps []chainStep
+	for _, m := range matchers {
+		builtin, ok := m.(builtinMatcher)
+		if !ok {
+			steps = append(steps, chainStep{matcher: m.Name(), matchStep: matchStep{
+				Method: MatchMethod(m.Name()),
+				Lookup: func() (*MatchCandidate, error) { return m.Match(p, env.Products) },
+			}})
+			continue
+		}
+		for _, step := range builtin.steps(p, env) {
+			steps = append(steps, chainStep{matcher: m.Name(), matchStep: step})
+		}
+	}
+	return steps
+}
+
+// scannerProducts looks products up through the scanner when a lookup runs, so building
+// the chain does not load the index
+type scannerProducts struct {
+	s *Scanner
+}
+
+func (l scannerProducts) LookupByPURL(purl string) (*db.Product, []db.Cycle, []db.ProductIdentifier, error) {
+	return l.s.lookups().LookupByPURL(purl)
+}
+
+func (l scannerProducts) LookupByPURLPrefix(purlType, packageName string) (*db.Product, []db.Cycle, error) {
+	return l.s.lookups().LookupByPURLPrefix(purlType, packageName)
+}
+
+func (l scannerProducts) LookupByCPE(cpeString string) (*db.Product, []db.Cycle, error) {
+	return l.s.lookups().LookupByCPE(cpeString)
+}
+
+func (l scannerProducts) LookupByName(name string, pkgType string) (*db.Product, []db.Cycle, error) {
+	return l.s.lookups().LookupByName(name, pkgType)
+}
+
+// candidateOf turns the result of a database lookup into a match candidate
+func candidateOf(product *db.Product, cycles []db.Cycle, err error) (*MatchCandidate, error) {
+	if err != nil || product == nil {
+		return nil, err
+	}
+	return &MatchCandidate{Product: product, Cycles: cycles}, nil
+}
+
+// userMappingSteps looks up the product of the user mapping matching a package. User
+// mappings take precedence over every built-in strategy.
+func userMappingSteps(p pkg.Package, env *matchEnv) []matchStep {
+	mapping := env.Mappings.findMapping(p)
+	if mapping == nil {
+		if env.Mappings == nil {
+			return nil
+		}
+		return []matchStep{{Method: MatchUserMapping, Skip: "no user mapping matched"}}
+	}
+	product := mapping.Product
+	return []matchStep{{
+		Method:  MatchUserMapping,
+		Query:   mapping.describe(),
+		Version: mapping.extractVersion(p),
+		Lookup: func() (*MatchCandidate, error) {
+			return candidateOf(env.Products.LookupByName(product, ""))
+		},
+		Miss: fmt.Sprintf("mapped product %q is not in the database", product),
+	}}
+}
+
+// exactPURLSteps looks up the package PURL
+func exactPURLSteps(p pkg.Package, env *matchEnv) []matchStep {
+	if p.PURL == "" {
+		return []matchStep{{Method: MatchExactPURL, Skip: "package has no PURL"}}
+	}
+	purl := p.PURL
+	return []matchStep{{
+		Method: MatchExactPURL,
+		Query:  purl,
+		Lookup: func() (*MatchCandidate, error) {
+			product, cycles, _, err := env.Products.LookupByPURL(purl)
+			return candidateOf(product, cycles, err)
+		},
+	}}
+}
+
+// runtimeSteps looks up runtimes found by syft's binary cataloger and the Go standard
+// library of Go binaries
+func runtimeSteps(p pkg.Package, env *matchEnv) []matchStep {
+	product, version, ok := runtimeProduct(p)
+	if !ok {
+		return []matchStep{{Method: MatchRuntime, Skip: "not a known runtime binary or Go stdlib"}}
+	}
+	return []matchStep{{
+		Method:  MatchRuntime,
+		Query:   fmt.Sprintf("%s %s", product, version),
+		Version: version,
+		Lookup: func() (*MatchCandidate, error) {
+			return candidateOf(env.Products.LookupByName(product, ""))
+		},
+	}}
+}
+
+// mavenSteps matches Java frameworks on their Maven groupId:artifactId
+func mavenSteps(p pkg.Package, env *matchEnv) []matchStep {
+	group, artifact := mavenCoordinates(p)
+	if group == "" {
+		return []matchStep{{Method: MatchMaven, Skip: "no Maven coordinates"}}
+	}
+	framework := findMavenFramework(group, artifact)
+	if framework == nil {
+		return []matchStep{{Method: MatchMaven, Skip: fmt.Sprintf("%s:%s is not a known framework", group, artifact)}}
+	}
+	products := framework.products
+	return []matchStep{{
+		Method: MatchMaven,
+		Query:  fmt.Sprintf("%s:%s", group, artifact),
+		Lookup: func() (*MatchCandidate, error) {
+			return candidateOf(lookupFirstProduct(env.Products, products))
+		},
+	}}
+}
+
+// versionedNameSteps matches distro packages such as python3.11 or openjdk-17-jre,
+// which carry the runtime cycle in their name (or in the name of their source package,
+// e.g. libpq5 built from postgresql-15)
+func versionedNameSteps(p pkg.Package, env *matchEnv) []matchStep {
+	pkgType := string(p.Type)
+	rule, cycle := parseVersionedName(p.Name, pkgType)
+	if source := sourcePackageName(p); rule == nil && source != "" {
+		rule, cycle = parseVersionedName(source, pkgType)
+	}
+	if rule == nil {
+		return []matchStep{{Method: MatchVersionedName, Skip: "package name carries no runtime cycle"}}
+	}
+	products := rule.products
+	return []matchStep{{
+		Method:  MatchVersionedName,
+		Query:   fmt.Sprintf("%s %s", strings.Join(products, "|"), cycle),
+		Version: cycleVersion(p.Version, pkgType, cycle),
+		Lookup: func() (*MatchCandidate, error) {
+			return candidateOf(lookupFirstProduct(env.Products, products))
+		},
+	}}
+}
+
+// distroPURLPrefixes returns the distro PURL types and namespaces a package type is
+// looked up under. The database has entries like pkg:deb/debian/nginx and
+// pkg:deb/ubuntu/python3.12.
+func distroPURLPrefixes(pkgType string) []string {
+	switch pkgType {
+	case "deb":
+		return []string{"deb/debian", "deb/ubuntu"}
+	case "rpm":
+		return []string{"rpm/fedora", "rpm/redhat", "rpm/centos", "rpm/amzn"}
+	case "apk":
+		return []string{"apk/alpine"}
+	}
+	return nil
+}
+
+// distroPURLSteps looks up deb, rpm and apk packages under distro PURLs
+func distroPURLSteps(p pkg.Package, env *matchEnv) []matchStep {
+	prefixes := distroPURLPrefixes(string(p.Type))
+	if len(prefixes) == 0 {
+		return []matchStep{{Method: MatchDistroPURL, Skip: "not a distro package"}}
+	}
+	var steps []matchStep
+	for _, prefix := range prefixes {
+		steps = append(steps, purlPrefixStep(env, MatchDistroPURL, prefix, p.Name))
+	}
+	return steps
+}
+
+// sourcePackageSteps looks up distro binaries by their source package (libssl3 →
+// openssl), which names the upstream project far more often than the binary does
+func sourcePackageSteps(p pkg.Package, env *matchEnv) []matchStep {
+	source := sourcePackageName(p)
+	if source == "" {
+		return []matchStep{{Method: MatchSourcePackage, Skip: "no source package other than the package itself"}}
+	}
+	var steps []matchStep
+	for _, prefix := range distroPURLPrefixes(string(p.Type)) {
+		steps = append(steps, purlPrefixStep(env, MatchSourcePackage, prefix, source))
+	}
+	pkgType := string(p.Type)
+	return append(steps, matchStep{
+		Method: MatchSourcePackage,
+		Query:  source,
+		Lookup: func() (*MatchCandidate, error) {
+			return candidateOf(env.Products.LookupByName(source, pkgType))
+		},
+	})
+}
+
+// purlPrefixSteps looks up language packages (pypi, npm, gem, etc.) under the PURL type
+// of their ecosystem
+func purlPrefixSteps(p pkg.Package, env *matchEnv) []matchStep {
+	pkgType := string(p.Type)
+	if group, artifact := mavenCoordinates(p); group != "" {
+		// Scope Maven lookups to the group, the artifact name alone is ambiguous
+		return []matchStep{purlPrefixStep(env, MatchPURLPrefix, "maven/"+group, artifact)}
+	}
+	if purlType := getPURLTypeFromPackageType(pkgType); purlType != "" {
+		return []matchStep{purlPrefixStep(env, MatchPURLPrefix, purlType, p.Name)}
+	}
+	return []matchStep{{Method: MatchPURLPrefix, Skip: fmt.Sprintf("no PURL type for package type %q", pkgType)}}
+}
+
+// genericPURLSteps looks up the package name under generic PURLs
+func genericPURLSteps(p pkg.Package, env *matchEnv) []matchStep {
+	return []matchStep{purlPrefixStep(env, MatchPURLPrefix, "generic", p.Name)}
+}
+
+// cpeSteps looks up each CPE of the package
+func cpeSteps(p pkg.Package, env *matchEnv) []matchStep {
+	if len(p.CPEs) == 0 {
+		return []matchStep{{Method: MatchCPE, Skip: "package has no CPEs"}}
+	}
+	var steps []matchStep
+	for _, c := range p.CPEs {
+		cpeStr := c.Attributes.String()
+		steps = append(steps, matchStep{
+			Method: MatchCPE,
+			Query:  cpeStr,
+			Lookup: func() (*MatchCandidate, error) {
+				return candidateOf(env.Products.LookupByCPE(cpeStr))
+			},
+		})
+	}
+	return steps
+}
+
+// nameSteps falls back to the package name (for products like nginx, postgresql, etc.)
+func nameSteps(p pkg.Package, env *matchEnv) []matchStep {
+	name, pkgType := p.Name, string(p.Type)
+	return []matchStep{{
+		Method: MatchName,
+		Query:  name,
+		Lookup: func() (*MatchCandidate, error) {
+			return candidateOf(env.Products.LookupByName(name, pkgType))
+		},
+	}}
+}
+
+// lookupFirstProduct looks up products by name in order and returns the first that exists
+func lookupFirstProduct(products db.ProductLookup, names []string) (*db.Product, []db.Cycle, error) {
+	for _, name := range names {
+		found, cycles, err := products.LookupByName(name, "")
+		if err != nil || found != nil {
+			return found, cycles, err
+		}
+	}
+	return nil, nil, nil
+}
+
+// purlPrefixStep builds a lookup step matching PURL identifiers of a type or namespace
+// and the full package name, whatever their version
+func purlPrefixStep(env *matchEnv, method MatchMethod, purlType, name string) matchStep {
+	return matchStep{
+		Method: method,
+		Query:  fmt.Sprintf("pkg:%s/%s", purlType, name),
+		Lookup: func() (*MatchCandidate, error) {
+			return candidateOf(env.Products.LookupByPURLPrefix(purlType, name))
+		},
+	}
+}
@@ -0,0 +1,74 @@
+package scanning_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/j0356/eol-scanner/core/db"
+	"github.com/j0356/eol-scanner/core/scanning"
+)
+
+// TestExternalMatcher tests that a matcher written outside the package is consulted by
+// a scan, with the version and confidence it returns
+func TestExternalMatcher(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	manager, err := db.NewEOLDatabaseManager(dbPath)
+	if err != nil {
+		t.Fatalf("NewEOLDatabaseManager() error = %v", err)
+	}
+	eol := true
+	productID, err := manager.UpsertProduct(db.ProductData{Name: "nodejs", Category: "lang"})
+	if err != nil {
+		t.Fatalf("UpsertProduct() error = %v", err)
+	}
+	if _, err := manager.UpsertCycle(productID, db.ReleaseData{Name: "16", ReleaseDate: "2021-04-20", IsEol: &eol, EolFrom: "2023-09-11"}); err != nil {
+		t.Fatalf("UpsertCycle() error = %v", err)
+	}
+	manager.Close()
+
+	// In-house runtimes are published as acme-node-<version>
+	acme := scanning.NewMatcher("acme_runtime", func(p pkg.Package, products db.ProductLookup) (*scanning.MatchCandidate, error) {
+		version, ok := strings.CutPrefix(p.Name, "acme-node-")
+		if !ok {
+			return nil, nil
+		}
+		product, cycles, err := products.LookupByName("nodejs", "")
+		if err != nil || product == nil {
+			return nil, err
+		}
+		return &scanning.MatchCandidate{Product: product, Cycles: cycles, Version: version, Confidence: scanning.ConfidenceMedium}, nil
+	})
+
+	config := scanning.DefaultScannerConfig()
+	config.DBPath = dbPath
+	config.AutoUpdateDB = false
+	config.Matchers = append([]scanning.Matcher{acme}, scanning.DefaultMatchers()...)
+	scanner, err := scanning.NewScanner(config)
+	if err != nil {
+		t.Fatalf("NewScanner() error = %v", err)
+	}
+	defer scanner.Close()
+
+	sbomResult := &sbom.SBOM{Artifacts: sbom.Artifacts{
+		Packages: pkg.NewCollection(pkg.Package{Name: "acme-node-16.20.2", Version: "1.0.0", Type: pkg.BinaryPkg}),
+	}}
+	summary, err := scanner.ScanSBOM(context.Background(), sbomResult, "acme")
+	if err != nil {
+		t.Fatalf("ScanSBOM() error = %v", err)
+	}
+
+	if len(summary.Components) != 1 {
+		t.Fatalf("ScanSBOM() found %d components, want 1", len(summary.Components))
+	}
+	c := summary.Components[0]
+	if c.MatchedProduct != "nodejs" || c.MatchedCycle != "16" || c.Status != scanning.StatusEOL {
+		t.Errorf("component = %s %s (%s), want nodejs 16 (%s)", c.MatchedProduct, c.MatchedCycle, c.Status, scanning.StatusEOL)
+	}
+	if c.Match == nil || c.Match.Method != "acme_runtime" || c.Match.Confidence != scanning.ConfidenceMedium {
+		t.Errorf("match = %+v, want acme_runtime with %s confidence", c.Match, scanning.ConfidenceMedium)
+	}
+}
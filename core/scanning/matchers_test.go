@@ -0,0 +1,162 @@
+package scanning
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/j0356/eol-scanner/core/db"
+)
+
+// matcherNames returns the names of a chain of matchers
+func matcherNames(matchers []Matcher) []string {
+	var names []string
+	for _, m := range matchers {
+		names = append(names, m.Name())
+	}
+	return names
+}
+
+// TestMatcherChain tests building the matching chain from the scanner configuration
+func TestMatcherChain(t *testing.T) {
+	custom := NewMatcher("internal", func(pkg.Package, db.ProductLookup) (*MatchCandidate, error) { return nil, nil })
+
+	tests := []struct {
+		name     string
+		matchers []Matcher
+		disabled []string
+		want     []string
+		wantErr  bool
+	}{
+		{"default", nil, nil, matcherNames(DefaultMatchers()), false},
+		{"disabled built-ins", nil, []string{"cpe", "name"}, []string{
+			"user_mapping", "exact_purl", "runtime", "maven_coordinates", "versioned_name",
+			"distro_purl", "source_package", "purl_prefix", "generic_purl",
+		}, false},
+		{"custom chain", []Matcher{custom, DefaultMatchers()[1]}, nil, []string{"internal", "exact_purl"}, false},
+		{"unknown disabled matcher", nil, []string{"nme"}, nil, true},
+		{"disabled matcher not in custom chain", []Matcher{custom}, []string{"cpe"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := matcherChain(&ScannerConfig{Matchers: tt.matchers, DisabledMatchers: tt.disabled})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("matcherChain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := matcherNames(chain); !slices.Equal(got, tt.want) {
+				t.Errorf("matcherChain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNewScannerUnknownMatcher tests that a misspelled disabled matcher is reported
+func TestNewScannerUnknownMatcher(t *testing.T) {
+	config := DefaultScannerConfig()
+	config.DisabledMatchers = []string{"cpes"}
+	_, err := NewScanner(config)
+	if err == nil || !strings.Contains(err.Error(), `unknown matcher "cpes"`) {
+		t.Errorf("NewScanner() error = %v, want an unknown matcher error", err)
+	}
+}
+
+// TestCustomMatcher tests that a library matcher inserted into the chain matches
+// packages the built-ins cannot, with the confidence it grades
+func TestCustomMatcher(t *testing.T) {
+	eol := true
+	scanner := newTestDBScanner(t, db.ProductData{Name: "nodejs", Category: "lang", Releases: []db.ReleaseData{
+		{Name: "16", ReleaseDate: "2021-04-20", IsEol: &eol, EolFrom: "2023-09-11"},
+	}})
+
+	// In-house runtimes are published as acme-node-<version>
+	internal := NewMatcher("acme_runtime", func(p pkg.Package, products db.ProductLookup) (*MatchCandidate, error) {
+		version, ok := strings.CutPrefix(p.Name, "acme-node-")
+		if !ok {
+			return nil, nil
+		}
+		product, cycles, err := products.LookupByName("nodejs", "")
+		if err != nil || product == nil {
+			return nil, err
+		}
+		return &MatchCandidate{Product: product, Cycles: cycles, Version: version, Confidence: ConfidenceMedium}, nil
+	})
+	chain, err := matcherChain(&ScannerConfig{Matchers: append([]Matcher{internal}, DefaultMatchers()...)})
+	if err != nil {
+		t.Fatalf("matcherChain() error = %v", err)
+	}
+	scanner.matchers = chain
+
+	result := scanner.checkComponent(pkg.Package{Name: "acme-node-16.20.2", Version: "1.0.0", Type: pkg.BinaryPkg})
+	if result.MatchedProduct != "nodejs" || result.MatchedCycle != "16" || result.Status != StatusEOL {
+		t.Errorf("checkComponent() = %s %s (%s), want nodejs 16 (%s)", result.MatchedProduct, result.MatchedCycle, result.Status, StatusEOL)
+	}
+	if result.Match == nil || result.Match.Method != "acme_runtime" || result.Match.Confidence != ConfidenceMedium {
+		t.Errorf("match = %+v, want acme_runtime with %s confidence", result.Match, ConfidenceMedium)
+	}
+
+	explanation := explainPackage(t, scanner, pkg.Package{Name: "acme-node-16.20.2", Type: pkg.BinaryPkg})
+	if len(explanation.Attempts) == 0 || explanation.Attempts[0].Matcher != "acme_runtime" || !explanation.Attempts[0].Selected {
+		t.Errorf("first attempt = %+v, want the selected acme_runtime lookup", explanation.Attempts)
+	}
+}
+
+// TestDisabledMatcher tests that a disabled built-in no longer matches packages
+func TestDisabledMatcher(t *testing.T) {
+	scanner := newTestDBScanner(t, db.ProductData{Name: "redis", Category: "database"})
+	p := pkg.Package{Name: "redis", Version: "7.2.4", Type: pkg.NpmPkg}
+
+	if result := scanner.checkComponent(p); result.MatchedProduct != "redis" {
+		t.Fatalf("checkComponent() matched %q, want redis by name", result.MatchedProduct)
+	}
+
+	chain, err := matcherChain(&ScannerConfig{DisabledMatchers: []string{"name"}})
+	if err != nil {
+		t.Fatalf("matcherChain() error = %v", err)
+	}
+	scanner.matchers = chain
+	if result := scanner.checkComponent(p); result.MatchedProduct != "" || result.Status != StatusUnknown {
+		t.Errorf("checkComponent() matched %q (%s), want no match with the name matcher disabled", result.MatchedProduct, result.Status)
+	}
+}
+
+// failingLookup is a ProductLookup whose every lookup fails, like a broken database
+type failingLookup struct{ err error }
+
+func (l failingLookup) LookupByPURL(string) (*db.Product, []db.Cycle, []db.ProductIdentifier, error) {
+	return nil, nil, nil, l.err
+}
+
+func (l failingLookup) LookupByPURLPrefix(string, string) (*db.Product, []db.Cycle, error) {
+	return nil, nil, l.err
+}
+
+func (l failingLookup) LookupByCPE(string) (*db.Product, []db.Cycle, error) {
+	return nil, nil, l.err
+}
+
+func (l failingLookup) LookupByName(string, string) (*db.Product, []db.Cycle, error) {
+	return nil, nil, l.err
+}
+
+// TestBuiltinMatcherLookupError tests that a built-in matcher reports a failing lookup
+// instead of treating the package as unknown
+func TestBuiltinMatcherLookupError(t *testing.T) {
+	errBroken := errors.New("database is locked")
+	p := pkg.Package{Name: "redis", Version: "7.2.4", Type: pkg.NpmPkg}
+
+	for _, m := range DefaultMatchers() {
+		if m.Name() != "name" {
+			continue
+		}
+		candidate, err := m.Match(p, failingLookup{errBroken})
+		if !errors.Is(err, errBroken) {
+			t.Errorf("Match() error = %v, want %v", err, errBroken)
+		}
+		if candidate != nil {
+			t.Errorf("Match() candidate = %+v, want nil", candidate)
+		}
+	}
+}
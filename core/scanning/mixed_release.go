@@ -0,0 +1,131 @@
+package scanning
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// OriginRelease is the older distro release a package was built for, when it differs
+// from the release of the image's OS. Such packages get no updates from the OS release.
+type OriginRelease struct {
+	Distro       string    `json:"distro"`
+	Release      string    `json:"release"`
+	Product      string    `json:"product,omitempty"`
+	Status       EOLStatus `json:"status"`
+	EOLDate      string    `json:"eol_date,omitempty"`
+	DaysUntilEOL *int      `json:"days_until_eol,omitempty"`
+}
+
+// distroFamilies maps os-release IDs to the distro whose releases their package
+// versions name. RHEL rebuilds ship packages with the same el dist tags.
+var distroFamilies = map[string]string{
+	"debian":    "debian",
+	"ubuntu":    "ubuntu",
+	"rhel":      "rhel",
+	"centos":    "rhel",
+	"rocky":     "rhel",
+	"almalinux": "rhel",
+	"ol":        "rhel",
+	"amzn":      "amzn",
+	"fedora":    "fedora",
+}
+
+// releaseOrigins flags packages built for an older release of the image's distro
+type releaseOrigins struct {
+	scanner   *Scanner
+	family    string
+	release   string
+	products  []string
+	evaluated map[string]OriginRelease
+}
+
+// newReleaseOrigins prepares the mixed-release check for an image's OS. Distros whose
+// package versions don't name a release return nil.
+func (s *Scanner) newReleaseOrigins(osInfo *OSInfo) *releaseOrigins {
+	if osInfo == nil {
+		return nil
+	}
+	family, ok := distroFamilies[strings.ToLower(osInfo.ID)]
+	if !ok {
+		return nil
+	}
+	version := osInfo.MatchedCycle
+	if version == "" {
+		version = osInfo.VersionID
+	}
+	release := familyRelease(family, version)
+	if release == "" {
+		return nil
+	}
+
+	// Origin releases are evaluated against the OS product, then the family's own
+	// product (el7 packages on a Rocky Linux 8 image are evaluated as RHEL 7)
+	var products []string
+	for _, product := range []string{osInfo.MatchedProduct, s.distroProduct(family)} {
+		if product != "" && !slices.Contains(products, product) {
+			products = append(products, product)
+		}
+	}
+
+	return &releaseOrigins{
+		scanner:   s,
+		family:    family,
+		release:   release,
+		products:  products,
+		evaluated: make(map[string]OriginRelease),
+	}
+}
+
+// familyRelease reduces an OS version to the release that package versions name: the
+// major version, or year.month for Ubuntu
+func familyRelease(family, version string) string {
+	parts := strings.Split(version, ".")
+	if family == "ubuntu" && len(parts) >= 2 {
+		return parts[0] + "." + parts[1]
+	}
+	return parts[0]
+}
+
+// check records the origin release of a package built for an older release of the
+// image's distro, e.g. a +deb10u3 package on Debian 12 or an el7 RPM on RHEL 8
+func (o *releaseOrigins) check(result *ComponentResult, p pkg.Package) {
+	if o == nil {
+		return
+	}
+	origin, ok := packageRelease(p)
+	if !ok || origin.id != o.family || compareCycleNames(origin.version, o.release) >= 0 {
+		return
+	}
+
+	info := o.evaluate(origin)
+	result.OriginRelease = &info
+}
+
+// evaluate returns the EOL status of an origin release, evaluated once per release
+func (o *releaseOrigins) evaluate(origin distroRelease) OriginRelease {
+	if info, ok := o.evaluated[origin.version]; ok {
+		return info
+	}
+
+	info := OriginRelease{Distro: origin.id, Release: origin.version, Status: StatusUnknown}
+	for _, name := range o.products {
+		product, cycles, err := o.scanner.lookups().LookupByName(name, "os")
+		if err != nil || product == nil {
+			continue
+		}
+		result := o.scanner.evaluateEOLStatus(ComponentResult{Status: StatusUnknown}, cycles, origin.version)
+		if result.MatchedCycle == "" {
+			continue
+		}
+		info.Product = product.Name
+		info.Status = result.Status
+		info.EOLDate = result.EOLDate
+		info.DaysUntilEOL = result.DaysUntilEOL
+		break
+	}
+
+	o.evaluated[origin.version] = info
+	return info
+}
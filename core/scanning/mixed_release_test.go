@@ -0,0 +1,141 @@
+package scanning
+
+import (
+	"testing"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/j0356/eol-scanner/core/db"
+)
+
+// TestFamilyRelease tests reducing OS versions to the release package versions name
+func TestFamilyRelease(t *testing.T) {
+	tests := []struct {
+		family  string
+		version string
+		want    string
+	}{
+		{"debian", "12", "12"},
+		{"debian", "12.4", "12"},
+		{"rhel", "8.9", "8"},
+		{"ubuntu", "22.04", "22.04"},
+		{"ubuntu", "22.04.3", "22.04"},
+		{"amzn", "2023", "2023"},
+	}
+
+	for _, tt := range tests {
+		if got := familyRelease(tt.family, tt.version); got != tt.want {
+			t.Errorf("familyRelease(%q, %q) = %q, want %q", tt.family, tt.version, got, tt.want)
+		}
+	}
+}
+
+// TestReleaseOrigins tests flagging packages built for an older release of the OS
+func TestReleaseOrigins(t *testing.T) {
+	eol, maintained := true, false
+	scanner := newTestDBScanner(t,
+		db.ProductData{Name: "debian", Category: "os", Releases: []db.ReleaseData{
+			{Name: "12", ReleaseDate: "2023-06-10", IsEol: &maintained, EolFrom: "2099-06-10"},
+			{Name: "10", ReleaseDate: "2019-07-06", IsEol: &eol, EolFrom: "2022-09-10"},
+		}},
+		db.ProductData{Name: "rhel", Category: "os", Releases: []db.ReleaseData{
+			{Name: "8", ReleaseDate: "2019-05-07", IsEol: &maintained, EolFrom: "2099-05-31"},
+			{Name: "7", ReleaseDate: "2014-06-09", IsEol: &eol, EolFrom: "2024-06-30"},
+		}},
+		db.ProductData{Name: "rocky-linux", Category: "os", Releases: []db.ReleaseData{
+			{Name: "8", ReleaseDate: "2021-06-21", IsEol: &maintained, EolFrom: "2099-05-31"},
+		}},
+	)
+
+	tests := []struct {
+		name    string
+		os      OSInfo
+		pkg     pkg.Package
+		release string
+		product string
+		status  EOLStatus
+	}{
+		{
+			name:    "older debian security update",
+			os:      OSInfo{ID: "debian", VersionID: "12", MatchedProduct: "debian", MatchedCycle: "12"},
+			pkg:     pkg.Package{Name: "libssl1.1", Version: "1.1.1n-0+deb10u3", Type: pkg.DebPkg},
+			release: "10", product: "debian", status: StatusEOL,
+		},
+		{
+			name: "current release",
+			os:   OSInfo{ID: "debian", VersionID: "12", MatchedProduct: "debian", MatchedCycle: "12"},
+			pkg:  pkg.Package{Name: "libssl3", Version: "3.0.11-1~deb12u2", Type: pkg.DebPkg},
+		},
+		{
+			name: "no release marker",
+			os:   OSInfo{ID: "debian", VersionID: "12", MatchedProduct: "debian", MatchedCycle: "12"},
+			pkg:  pkg.Package{Name: "curl", Version: "7.88.1-10", Type: pkg.DebPkg},
+		},
+		{
+			name:    "el7 rpm on rocky 8 falls back to rhel",
+			os:      OSInfo{ID: "rocky", VersionID: "8.9", MatchedProduct: "rocky-linux", MatchedCycle: "8"},
+			pkg:     pkg.Package{Name: "compat-openssl10", Version: "1:1.0.2k-26.el7_9", Type: pkg.RpmPkg},
+			release: "7", product: "rhel", status: StatusEOL,
+		},
+		{
+			name: "other distro family",
+			os:   OSInfo{ID: "ubuntu", VersionID: "22.04", MatchedCycle: "22.04"},
+			pkg:  pkg.Package{Name: "libssl1.1", Version: "1.1.1n-0+deb10u3", Type: pkg.DebPkg},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origins := scanner.newReleaseOrigins(&tt.os)
+			var result ComponentResult
+			origins.check(&result, tt.pkg)
+
+			if tt.release == "" {
+				if result.OriginRelease != nil {
+					t.Errorf("check() flagged origin %+v, want none", result.OriginRelease)
+				}
+				return
+			}
+			o := result.OriginRelease
+			if o == nil {
+				t.Fatal("check() flagged no origin release")
+			}
+			if o.Release != tt.release || o.Product != tt.product || o.Status != tt.status || o.EOLDate == "" {
+				t.Errorf("origin = %+v, want release %s of %s (%s)", o, tt.release, tt.product, tt.status)
+			}
+		})
+	}
+
+	if scanner.newReleaseOrigins(&OSInfo{ID: "alpine", VersionID: "3.18"}) != nil {
+		t.Error("newReleaseOrigins() returned a check for a distro without release markers")
+	}
+	var none *releaseOrigins
+	none.check(&ComponentResult{}, pkg.Package{})
+}
+
+// TestReleaseOriginsDistroMapping tests that origin releases follow the user's distro
+// mappings
+func TestReleaseOriginsDistroMapping(t *testing.T) {
+	eol := true
+	scanner := newTestDBScanner(t, db.ProductData{Name: "acme-rhel", Category: "os", Releases: []db.ReleaseData{
+		{Name: "7", ReleaseDate: "2014-06-09", IsEol: &eol, EolFrom: "2024-06-30"},
+	}})
+	scanner.mappings = &MappingConfig{Distros: map[string]string{"rhel": "acme-rhel"}}
+
+	origins := scanner.newReleaseOrigins(&OSInfo{ID: "rhel", VersionID: "8.9"})
+	var result ComponentResult
+	origins.check(&result, pkg.Package{Name: "compat-openssl10", Version: "1:1.0.2k-26.el7_9", Type: pkg.RpmPkg})
+	if o := result.OriginRelease; o == nil || o.Product != "acme-rhel" || o.Status != StatusEOL {
+		t.Errorf("origin = %+v, want release 7 of acme-rhel (%s)", o, StatusEOL)
+	}
+}
+
+// TestScanSummaryMixedRelease tests counting packages from an older distro release
+func TestScanSummaryMixedRelease(t *testing.T) {
+	summary := &ScanSummary{}
+	summary.addComponent(ComponentResult{Name: "libssl1.1", Status: StatusUnknown, OriginRelease: &OriginRelease{Distro: "debian", Release: "10"}})
+	summary.addComponent(ComponentResult{Name: "libssl3", Status: StatusUnknown})
+
+	if summary.MixedReleaseComponents != 1 {
+		t.Errorf("MixedReleaseComponents = %d, want 1", summary.MixedReleaseComponents)
+	}
+}
@@ -0,0 +1,96 @@
+package scanning
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/anchore/syft/syft/linux"
+	"github.com/j0356/eol-scanner/core/db"
+)
+
+// rollingDistros are distributions without releases; they have no cycle to evaluate
+var rollingDistros = map[string]bool{
+	"arch":                true,
+	"archarm":             true,
+	"manjaro":             true,
+	"endeavouros":         true,
+	"gentoo":              true,
+	"void":                true,
+	"opensuse-tumbleweed": true,
+	"wolfi":               true,
+	"chainguard":          true,
+}
+
+// rollingMarkers are words in os-release versions and names that identify rolling or
+// testing branches: Debian sid (testing images report "trixie/sid"), Kali's
+// kali-rolling, Alpine edge and Fedora Rawhide
+var rollingMarkers = map[string]bool{
+	"sid":        true,
+	"unstable":   true,
+	"rolling":    true,
+	"edge":       true,
+	"rawhide":    true,
+	"tumbleweed": true,
+}
+
+// isRollingRelease reports whether an os-release describes a rolling or testing release
+func isRollingRelease(distro *linux.Release) bool {
+	if rollingDistros[strings.ToLower(distro.ID)] {
+		return true
+	}
+	for _, value := range []string{distro.VersionID, distro.VersionCodename, distro.Version, distro.PrettyName, distro.BuildID} {
+		for _, word := range releaseWords(value) {
+			if rollingMarkers[word] {
+				return true
+			}
+		}
+	}
+	// Alpine edge reports pre-release versions such as 3.21.0_alpha20240807
+	return strings.Contains(distro.VersionID, "_alpha")
+}
+
+// releaseWords splits an os-release value into lowercase words
+func releaseWords(value string) []string {
+	return strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// osCodename returns the release codename of an os-release: VERSION_CODENAME, or the
+// codename in parentheses in VERSION ("22.04.3 LTS (Jammy Jellyfish)", "12 (bookworm)")
+func osCodename(distro *linux.Release) string {
+	if distro.VersionCodename != "" {
+		return strings.ToLower(distro.VersionCodename)
+	}
+	start := strings.IndexByte(distro.Version, '(')
+	end := strings.LastIndexByte(distro.Version, ')')
+	if start < 0 || end < start {
+		return ""
+	}
+	if words := releaseWords(distro.Version[start+1 : end]); len(words) > 0 {
+		return words[0]
+	}
+	return ""
+}
+
+// findCycleByCodename returns the cycle whose codename matches an os-release codename.
+// endoflife.date records full codenames ("Jammy Jellyfish") where os-release only
+// has the first word ("jammy"), so cycles match on either.
+func findCycleByCodename(cycles []db.Cycle, codename string) *db.Cycle {
+	if codename == "" {
+		return nil
+	}
+	for i := range cycles {
+		if !cycles[i].Codename.Valid {
+			continue
+		}
+		words := releaseWords(cycles[i].Codename.String)
+		if len(words) == 0 {
+			continue
+		}
+		if strings.EqualFold(cycles[i].Codename.String, codename) || words[0] == codename {
+			return &cycles[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package scanning
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/anchore/syft/syft/linux"
+	"github.com/j0356/eol-scanner/core/db"
+)
+
+// newTestDBScanner returns a scanner backed by a temporary database holding the given products
+func newTestDBScanner(t testing.TB, products ...db.ProductData) *Scanner {
+	t.Helper()
+	manager, err := db.NewEOLDatabaseManager(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewEOLDatabaseManager() error = %v", err)
+	}
+	t.Cleanup(func() { manager.Close() })
+
+	for _, product := range products {
+		productID, err := manager.UpsertProduct(product)
+		if err != nil {
+			t.Fatalf("UpsertProduct(%s) error = %v", product.Name, err)
+		}
+		if _, err := manager.UpsertIdentifiers(productID, product.Identifiers); err != nil {
+			t.Fatalf("UpsertIdentifiers(%s) error = %v", product.Name, err)
+		}
+		for _, release := range product.Releases {
+			if _, err := manager.UpsertCycle(productID, release); err != nil {
+				t.Fatalf("UpsertCycle(%s %s) error = %v", product.Name, release.Name, err)
+			}
+		}
+	}
+	return &Scanner{config: DefaultScannerConfig(), dbManager: manager}
+}
+
+// osProducts are OS products with releases that ended long ago or end far in the future
+func osProducts() []db.ProductData {
+	eol, maintained := true, false
+	return []db.ProductData{
+		{Name: "debian", Category: "os", Releases: []db.ReleaseData{
+			{Name: "12", Codename: "Bookworm", ReleaseDate: "2023-06-10", IsEol: &maintained, EolFrom: "2099-06-10"},
+			{Name: "9", Codename: "Stretch", ReleaseDate: "2017-06-17", IsEol: &eol, EolFrom: "2020-07-06"},
+		}},
+		{Name: "ubuntu", Category: "os", Releases: []db.ReleaseData{
+			{Name: "22.04", Codename: "Jammy Jellyfish", ReleaseDate: "2022-04-21", IsEol: &maintained, EolFrom: "2099-04-01"},
+		}},
+		{Name: "alpine-linux", Category: "os", Releases: []db.ReleaseData{
+			{Name: "3.18", ReleaseDate: "2023-05-09", IsEol: &maintained, EolFrom: "2099-05-09"},
+		}},
+		{Name: "arch", Category: "os"},
+	}
+}
+
+// TestCheckOSEOL tests resolving OS releases by version, point release and codename
+func TestCheckOSEOL(t *testing.T) {
+	scanner := newTestDBScanner(t, osProducts()...)
+
+	tests := []struct {
+		name       string
+		distro     linux.Release
+		product    string
+		cycle      string
+		status     EOLStatus
+		identifier string
+	}{
+		{"version id", linux.Release{ID: "debian", VersionID: "9"}, "debian", "9", StatusEOL, "ID=debian"},
+		{"alpine point release", linux.Release{ID: "alpine", VersionID: "3.18.4"}, "alpine-linux", "3.18", StatusActive, "ID=alpine"},
+		{"codename only", linux.Release{ID: "debian", VersionCodename: "bookworm"}, "debian", "12", StatusActive, "VERSION_CODENAME=bookworm"},
+		{"codename first word", linux.Release{ID: "ubuntu", VersionCodename: "jammy"}, "ubuntu", "22.04", StatusActive, "VERSION_CODENAME=jammy"},
+		{"codename in version", linux.Release{ID: "debian", Version: "9 (stretch)"}, "debian", "9", StatusEOL, "ID=debian"},
+		{"debian testing", linux.Release{ID: "debian", VersionCodename: "trixie", PrettyName: "Debian GNU/Linux trixie/sid"}, "debian", "", StatusRolling, "ID=debian"},
+		{"arch", linux.Release{ID: "arch", BuildID: "rolling"}, "arch", "", StatusRolling, "ID=arch"},
+		{"tumbleweed without product", linux.Release{ID: "opensuse-tumbleweed", VersionID: "20240101"}, "", "", StatusRolling, ""},
+		{"unknown codename", linux.Release{ID: "debian", VersionCodename: "forky"}, "debian", "", StatusUnknown, "ID=debian"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := scanner.checkOSEOL(&tt.distro)
+			if info.MatchedProduct != tt.product || info.MatchedCycle != tt.cycle || info.Status != tt.status {
+				t.Errorf("checkOSEOL() = %q %q (%s), want %q %q (%s)",
+					info.MatchedProduct, info.MatchedCycle, info.Status, tt.product, tt.cycle, tt.status)
+			}
+			identifier := ""
+			if info.Match != nil {
+				identifier = info.Match.Identifier
+			}
+			if identifier != tt.identifier {
+				t.Errorf("identifier = %q, want %q", identifier, tt.identifier)
+			}
+		})
+	}
+}
+
+// TestIsRollingRelease tests detection of rolling and testing releases
+func TestIsRollingRelease(t *testing.T) {
+	tests := []struct {
+		name   string
+		distro linux.Release
+		want   bool
+	}{
+		{"arch", linux.Release{ID: "arch"}, true},
+		{"tumbleweed", linux.Release{ID: "opensuse-tumbleweed"}, true},
+		{"wolfi", linux.Release{ID: "wolfi", VersionID: "20230201"}, true},
+		{"debian sid", linux.Release{ID: "debian", VersionCodename: "sid"}, true},
+		{"debian testing", linux.Release{ID: "debian", PrettyName: "Debian GNU/Linux trixie/sid"}, true},
+		{"kali", linux.Release{ID: "kali", VersionID: "2024.1", VersionCodename: "kali-rolling"}, true},
+		{"alpine edge", linux.Release{ID: "alpine", VersionID: "3.21.0_alpha20240807", PrettyName: "Alpine Linux edge"}, true},
+		{"debian stable", linux.Release{ID: "debian", VersionID: "12", VersionCodename: "bookworm"}, false},
+		{"ubuntu", linux.Release{ID: "ubuntu", VersionID: "22.04", Version: "22.04.3 LTS (Jammy Jellyfish)"}, false},
+		{"word inside name", linux.Release{ID: "mycorp", PrettyName: "Insider Linux"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRollingRelease(&tt.distro); got != tt.want {
+				t.Errorf("isRollingRelease() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestOSCodename tests extracting the release codename from os-release fields
+func TestOSCodename(t *testing.T) {
+	tests := []struct {
+		distro linux.Release
+		want   string
+	}{
+		{linux.Release{VersionCodename: "Bookworm"}, "bookworm"},
+		{linux.Release{Version: "22.04.3 LTS (Jammy Jellyfish)"}, "jammy"},
+		{linux.Release{Version: "12 (bookworm)"}, "bookworm"},
+		{linux.Release{Version: "3.18.4"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := osCodename(&tt.distro); got != tt.want {
+			t.Errorf("osCodename(%+v) = %q, want %q", tt.distro, got, tt.want)
+		}
+	}
+}
+
+// TestFindCycleByCodename tests matching codenames against full cycle codenames
+func TestFindCycleByCodename(t *testing.T) {
+	cycles := []db.Cycle{
+		{Cycle: "24.04", Codename: sql.NullString{String: "Noble Numbat", Valid: true}},
+		{Cycle: "22.04", Codename: sql.NullString{String: "Jammy Jellyfish", Valid: true}},
+		{Cycle: "21.10"},
+	}
+
+	if cycle := findCycleByCodename(cycles, "jammy"); cycle == nil || cycle.Cycle != "22.04" {
+		t.Errorf("findCycleByCodename(jammy) = %+v, want 22.04", cycle)
+	}
+	if cycle := findCycleByCodename(cycles, "jellyfish"); cycle != nil {
+		t.Errorf("findCycleByCodename(jellyfish) = %+v, want nil", cycle)
+	}
+	if cycle := findCycleByCodename(cycles, ""); cycle != nil {
+		t.Errorf("findCycleByCodename(\"\") = %+v, want nil", cycle)
+	}
+}
@@ -0,0 +1,288 @@
+package scanning
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// versionedName recognizes distro packages that encode a runtime release cycle in their
+// name, such as python3.11, openjdk-17-jre-headless or php8.1-fpm. The capture groups
+// of the pattern are joined with "." to form the cycle.
+type versionedName struct {
+	pattern  *regexp.Regexp
+	products []string // endoflife.date products, tried in order
+	types    []string // Syft package types the pattern applies to (all distro types if empty)
+}
+
+// versionedNames lists the known versioned package name patterns. Names are lowercased
+// before matching.
+var versionedNames = []versionedName{
+	// python3.11, python3.11-minimal, libpython3.9-stdlib, python39 (RHEL), python311-libs
+	{pattern: regexp.MustCompile(`^(?:lib)?python([23])\.?(\d{1,2})(?:[-_].*)?$`), products: []string{"python"}},
+	// java-17-openjdk-headless, java-1.8.0-openjdk (RHEL builds)
+	{pattern: regexp.MustCompile(`^java-(\d+)-openjdk(?:-.*)?$`), products: []string{"redhat-build-of-openjdk"}, types: []string{"rpm"}},
+	{pattern: regexp.MustCompile(`^java-1\.(\d+)\.0-openjdk(?:-.*)?$`), products: []string{"redhat-build-of-openjdk"}, types: []string{"rpm"}},
+	// openjdk-17-jre-headless (Debian, Ubuntu), openjdk17-jre (Alpine); these follow the
+	// OpenJDK updates project, whose support matches Eclipse Temurin
+	{pattern: regexp.MustCompile(`^openjdk-?(\d+)(?:-.*)?$`), products: []string{"eclipse-temurin"}},
+	// postgresql-15, postgresql-client-15, postgresql15-server (PGDG), postgresql-9.6
+	{pattern: regexp.MustCompile(`^postgresql-?(\d+(?:\.\d+)?)(?:-.*)?$`), products: []string{"postgresql"}},
+	{pattern: regexp.MustCompile(`^postgresql-(?:client|contrib|server-dev|plperl|plpython3|pltcl)-(\d+(?:\.\d+)?)$`), products: []string{"postgresql"}},
+	// nodejs18, nodejs-18
+	{pattern: regexp.MustCompile(`^nodejs-?(\d+)(?:-.*)?$`), products: []string{"nodejs"}},
+	// php8.1-fpm, php81-fpm (Alpine), libapache2-mod-php7.4
+	{pattern: regexp.MustCompile(`^(?:libapache2-mod-)?php-?(\d)\.?(\d)(?:-.*)?$`), products: []string{"php"}},
+	// ruby3.0, libruby3.0, ruby2.7-dev
+	{pattern: regexp.MustCompile(`^(?:lib)?ruby-?(\d)\.?(\d)(?:-.*)?$`), products: []string{"ruby"}},
+	// golang-1.21-go, golang-1.21-src
+	{pattern: regexp.MustCompile(`^golang-(\d)\.(\d+)(?:-.*)?$`), products: []string{"go"}},
+}
+
+// parseVersionedName returns the pattern and release cycle encoded in the name of a
+// distro package, or nil if the name carries no cycle
+func parseVersionedName(name, pkgType string) (*versionedName, string) {
+	if !isDistroPackage(pkgType) {
+		return nil, ""
+	}
+
+	name = strings.ToLower(name)
+	for i := range versionedNames {
+		n := &versionedNames[i]
+		if len(n.types) > 0 && !slices.Contains(n.types, pkgType) {
+			continue
+		}
+		match := n.pattern.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		return n, strings.Join(match[1:], ".")
+	}
+	return nil, ""
+}
+
+// cycleVersion returns the version to evaluate for a package whose cycle comes from its
+// name. The package version is kept when it belongs to the cycle (it carries the patch
+// level); otherwise, e.g. for metapackages versioned independently, the cycle is used.
+func cycleVersion(version, pkgType, cycle string) string {
+	if ParseVersion(version, versionSchemeForType(pkgType)).MatchesCycle(cycle) {
+		return version
+	}
+	return cycle
+}
+
+// isDistroPackage reports whether a Syft package type is an OS package manager type
+func isDistroPackage(pkgType string) bool {
+	switch pkgType {
+	case "deb", "rpm", "apk":
+		return true
+	default:
+		return false
+	}
+}
+
+// sourcePackageName returns the source package a distro binary package was built from:
+// the dpkg Source field, the apk origin or the name of the source RPM. Binaries named
+// after their source (and packages without source metadata) return "".
+func sourcePackageName(p pkg.Package) string {
+	var source string
+	switch m := p.Metadata.(type) {
+	case pkg.DpkgDBEntry:
+		source = m.Source
+	case *pkg.DpkgDBEntry:
+		source = m.Source
+	case pkg.ApkDBEntry:
+		source = m.OriginPackage
+	case *pkg.ApkDBEntry:
+		source = m.OriginPackage
+	case pkg.RpmDBEntry:
+		source = sourceRPMName(m.SourceRpm)
+	case *pkg.RpmDBEntry:
+		source = sourceRPMName(m.SourceRpm)
+	}
+
+	// dpkg may record the source version as "openssl (3.0.11-1)"
+	if idx := strings.IndexByte(source, ' '); idx >= 0 {
+		source = source[:idx]
+	}
+	if strings.EqualFold(source, p.Name) {
+		return ""
+	}
+	return source
+}
+
+// packageSource returns the source package a distro package belongs to, falling back to
+// the package itself when it is named after its source (dpkg then omits the Source
+// field), so that openssl and libssl3 share a source. Other packages have none.
+func packageSource(p pkg.Package) string {
+	if source := sourcePackageName(p); source != "" {
+		return source
+	}
+	if isDistroPackage(string(p.Type)) {
+		return p.Name
+	}
+	return ""
+}
+
+// sourceRPMName extracts the package name from a source RPM file name
+// (openssl-3.0.7-25.el9.src.rpm → openssl)
+func sourceRPMName(sourceRPM string) string {
+	name := strings.TrimSuffix(strings.TrimSuffix(sourceRPM, ".src.rpm"), ".nosrc.rpm")
+	if name == sourceRPM {
+		return ""
+	}
+	// Drop the release, then the version
+	for range 2 {
+		idx := strings.LastIndexByte(name, '-')
+		if idx <= 0 {
+			return ""
+		}
+		name = name[:idx]
+	}
+	return name
+}
+
+// collapseBySource merges matched components built from the same source package into
+// one finding, e.g. libssl3 and openssl into a single OpenSSL result. Components only
+// collapse when they share the source, version, product and cycle, so the merged
+// finding is identical for each of them. The order of first occurrence is kept, and the
+// merged finding is attributed to the layer of its first binary.
+func collapseBySource(results []ComponentResult) []ComponentResult {
+	type sourceKey struct {
+		pkgType, source, version, product, cycle string
+	}
+
+	collapsed := make([]ComponentResult, 0, len(results))
+	index := make(map[sourceKey]int)
+	for _, r := range results {
+		if r.SourcePackage == "" || r.MatchedProduct == "" {
+			collapsed = append(collapsed, r)
+			continue
+		}
+
+		key := sourceKey{r.Type, r.SourcePackage, r.Version, r.MatchedProduct, r.MatchedCycle}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(collapsed)
+			r.Binaries = []string{r.Name}
+			collapsed = append(collapsed, r)
+			continue
+		}
+
+		merged := &collapsed[i]
+		merged.Binaries = append(merged.Binaries, r.Name)
+		merged.Name = merged.SourcePackage
+		for _, location := range r.Locations {
+			if !slices.Contains(merged.Locations, location) {
+				merged.Locations = append(merged.Locations, location)
+			}
+		}
+	}
+	return collapsed
+}
+
+// binaryRuntimes maps the package names syft's binary cataloger gives the runtimes and
+// servers it detects by file signature to endoflife.date products. OpenJDK builds
+// without a known vendor are evaluated against Eclipse Temurin, as for distro packages.
+var binaryRuntimes = map[string]string{
+	"busybox":                    "busybox",
+	"consul":                     "consul",
+	"curl":                       "curl",
+	"elixir":                     "elixir",
+	"envoy":                      "envoy",
+	"erlang":                     "erlang",
+	"ffmpeg":                     "ffmpeg",
+	"fluent-bit":                 "fluent-bit",
+	"gcc":                        "gcc",
+	"github.com/hashicorp/vault": "hashicorp-vault",
+	"go":                         "go",
+	"grafana":                    "grafana",
+	"graalvm":                    "graalvm",
+	"haproxy":                    "haproxy",
+	"haskell/ghc":                "ghc",
+	"helm":                       "helm",
+	"httpd":                      "apache-http-server",
+	"jdk":                        "eclipse-temurin",
+	"jre":                        "eclipse-temurin",
+	"mariadb":                    "mariadb",
+	"mongodb":                    "mongodb",
+	"mysql":                      "mysql",
+	"nginx":                      "nginx",
+	"node":                       "nodejs",
+	"openjdk":                    "eclipse-temurin",
+	"openssl":                    "openssl",
+	"percona-server":             "percona-server",
+	"perl":                       "perl",
+	"php":                        "php",
+	"pilot-agent":                "istio",
+	"pilot-discovery":            "istio",
+	"postgresql":                 "postgresql",
+	"pypy":                       "pypy",
+	"python":                     "python",
+	"redis":                      "redis",
+	"ruby":                       "ruby",
+	"rust":                       "rust",
+	"traefik":                    "traefik",
+	"valkey":                     "valkey",
+	"zulu":                       "azul-zulu",
+}
+
+// runtimeProduct returns the product and release version of a runtime found by syft's
+// binary cataloger, of a JDK installation, of the Go standard library compiled into a
+// Go binary, or of the .NET runtime and the NuGet packages that ship with it
+func runtimeProduct(p pkg.Package) (string, string, bool) {
+	if release, ok := javaRelease(p); ok {
+		// A JDK release file names its vendor, which decides the support timeline
+		product := javaVendorProduct(release)
+		if strings.EqualFold(p.Name, "graalvm") {
+			product = "graalvm"
+		}
+		return product, javaVersion(p.Version), true
+	}
+
+	switch {
+	case p.Type == pkg.BinaryPkg:
+		product, ok := binaryRuntimes[strings.ToLower(p.Name)]
+		if !ok {
+			return "", "", false
+		}
+		switch product {
+		case "go":
+			return product, goVersion(p.Version), true
+		case "eclipse-temurin", "azul-zulu", "graalvm":
+			return product, javaVersion(p.Version), true
+		}
+		return product, p.Version, true
+	case p.Type == pkg.GoModulePkg && p.Name == "stdlib":
+		return "go", goVersion(p.Version), true
+	case p.Type == pkg.DotnetPkg && (isDotnetRuntime(p.Name) || isDotnetFrameworkPackage(p.Name)):
+		return "dotnet", p.Version, true
+	default:
+		return "", "", false
+	}
+}
+
+// goVersion converts a Go toolchain version ("go1.21.5", "go1.22.0 X:boringcrypto")
+// to a release version
+func goVersion(version string) string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "go")
+	if idx := strings.IndexAny(version, " +"); idx >= 0 {
+		version = version[:idx]
+	}
+	return version
+}
+
+// javaVersion converts legacy Java versions (1.8.0_392-b08) to the modern scheme (8.0.392)
+// used by endoflife.date cycles. Other versions are returned unchanged.
+func javaVersion(version string) string {
+	rest, ok := strings.CutPrefix(version, "1.")
+	if !ok || len(rest) == 0 || !isDigit(rest[0]) || !strings.Contains(rest, "_") {
+		return version
+	}
+	if idx := strings.Index(rest, "-"); idx >= 0 {
+		rest = rest[:idx]
+	}
+	return strings.Replace(rest, "_", ".", 1)
+}
@@ -0,0 +1,338 @@
+package scanning
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// TestParseVersionedName tests extracting the product and cycle from distro package names
+func TestParseVersionedName(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkgType string
+		product string
+		cycle   string
+	}{
+		{"python3.11", "deb", "python", "3.11"},
+		{"python3.11-minimal", "deb", "python", "3.11"},
+		{"libpython3.9", "deb", "python", "3.9"},
+		{"libpython3.9-stdlib", "deb", "python", "3.9"},
+		{"python39", "rpm", "python", "3.9"},
+		{"python311-libs", "rpm", "python", "3.11"},
+		{"python2.7", "deb", "python", "2.7"},
+		{"openjdk-17-jre-headless", "deb", "eclipse-temurin", "17"},
+		{"openjdk17-jre", "apk", "eclipse-temurin", "17"},
+		{"java-17-openjdk-headless", "rpm", "redhat-build-of-openjdk", "17"},
+		{"java-1.8.0-openjdk", "rpm", "redhat-build-of-openjdk", "8"},
+		{"postgresql-15", "deb", "postgresql", "15"},
+		{"postgresql-client-15", "deb", "postgresql", "15"},
+		{"postgresql15-server", "rpm", "postgresql", "15"},
+		{"postgresql-9.6", "deb", "postgresql", "9.6"},
+		{"nodejs18", "rpm", "nodejs", "18"},
+		{"php8.1-fpm", "deb", "php", "8.1"},
+		{"php81-fpm", "apk", "php", "8.1"},
+		{"libapache2-mod-php7.4", "deb", "php", "7.4"},
+		{"ruby3.0", "deb", "ruby", "3.0"},
+		{"libruby3.0", "deb", "ruby", "3.0"},
+		{"golang-1.21-go", "deb", "go", "1.21"},
+		{"Python3.11", "deb", "python", "3.11"},
+
+		// No cycle in the name
+		{"python3", "deb", "", ""},
+		{"python3-requests", "deb", "", ""},
+		{"libpython3-stdlib", "deb", "", ""},
+		{"postgresql-client-common", "deb", "", ""},
+		{"nodejs", "deb", "", ""},
+		{"php-common", "deb", "", ""},
+		{"ruby-rack", "deb", "", ""},
+		{"golang-github-foo-bar", "deb", "", ""},
+
+		// Only distro packages, and RHEL patterns only for rpm
+		{"python3.11", "python", "", ""},
+		{"java-17-openjdk", "deb", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.pkgType, func(t *testing.T) {
+			rule, cycle := parseVersionedName(tt.name, tt.pkgType)
+			product := ""
+			if rule != nil {
+				product = rule.products[0]
+			}
+			if product != tt.product || cycle != tt.cycle {
+				t.Errorf("parseVersionedName(%q, %q) = %q %q, want %q %q", tt.name, tt.pkgType, product, cycle, tt.product, tt.cycle)
+			}
+		})
+	}
+}
+
+// TestCycleVersion tests that the package version is only replaced when it is outside the cycle
+func TestCycleVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		pkgType string
+		cycle   string
+		want    string
+	}{
+		{"3.11.2-6", "deb", "3.11", "3.11.2-6"},
+		{"17.0.9+9-1~deb12u1", "deb", "17", "17.0.9+9-1~deb12u1"},
+		{"1:8.1.2-1ubuntu2.14", "deb", "8.1", "1:8.1.2-1ubuntu2.14"},
+		{"15.5-0+deb12u1", "deb", "15", "15.5-0+deb12u1"},
+		{"1.8.0.392.b08-4.el8", "rpm", "8", "8"},
+		{"253", "deb", "15", "15"},
+		{"", "deb", "3.9", "3.9"},
+	}
+
+	for _, tt := range tests {
+		if got := cycleVersion(tt.version, tt.pkgType, tt.cycle); got != tt.want {
+			t.Errorf("cycleVersion(%q, %q, %q) = %q, want %q", tt.version, tt.pkgType, tt.cycle, got, tt.want)
+		}
+	}
+}
+
+// TestMatchStepsVersionedName tests that versioned names are tried before distro PURLs
+func TestMatchStepsVersionedName(t *testing.T) {
+	scanner := &Scanner{config: DefaultScannerConfig()}
+
+	steps := scanner.matchSteps(pkg.Package{
+		Name:    "openjdk-17-jre-headless",
+		Version: "17.0.9+9-1~deb12u1",
+		Type:    pkg.DebPkg,
+		PURL:    "pkg:deb/debian/openjdk-17-jre-headless@17.0.9%2B9-1~deb12u1",
+	})
+
+	step := steps[3]
+	if step.Method != MatchVersionedName || step.Lookup == nil {
+		t.Fatalf("fourth step = %s (skipped %v), want an active versioned name step", step.Method, step.Lookup == nil)
+	}
+	if step.Query != "eclipse-temurin 17" {
+		t.Errorf("query = %q, want %q", step.Query, "eclipse-temurin 17")
+	}
+	if step.Version != "17.0.9+9-1~deb12u1" {
+		t.Errorf("version = %q, want the package version", step.Version)
+	}
+	if steps[4].Method != MatchDistroPURL {
+		t.Errorf("fifth step = %s, want %s", steps[4].Method, MatchDistroPURL)
+	}
+}
+
+// TestSourcePackageName tests reading the source package from dpkg, apk and rpm metadata
+func TestSourcePackageName(t *testing.T) {
+	tests := []struct {
+		name string
+		pkg  pkg.Package
+		want string
+	}{
+		{"dpkg source", pkg.Package{Name: "libssl3", Metadata: pkg.DpkgDBEntry{Source: "openssl"}}, "openssl"},
+		{"dpkg source with version", pkg.Package{Name: "libssl3", Metadata: pkg.DpkgDBEntry{Source: "openssl (3.0.11-1)"}}, "openssl"},
+		{"dpkg pointer metadata", pkg.Package{Name: "libssl3", Metadata: &pkg.DpkgDBEntry{Source: "openssl"}}, "openssl"},
+		{"dpkg source is the package", pkg.Package{Name: "openssl", Metadata: pkg.DpkgDBEntry{Source: "openssl"}}, ""},
+		{"dpkg without source", pkg.Package{Name: "nginx", Metadata: pkg.DpkgDBEntry{}}, ""},
+		{"apk origin", pkg.Package{Name: "libcrypto3", Metadata: pkg.ApkDBEntry{OriginPackage: "openssl"}}, "openssl"},
+		{"rpm source", pkg.Package{Name: "openssl-libs", Metadata: pkg.RpmDBEntry{SourceRpm: "openssl-3.0.7-25.el9.src.rpm"}}, "openssl"},
+		{"rpm source with dashes", pkg.Package{Name: "python3-libs", Metadata: pkg.RpmDBEntry{SourceRpm: "python3.11-3.11.5-1.el9_3.src.rpm"}}, "python3.11"},
+		{"no metadata", pkg.Package{Name: "express"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sourcePackageName(tt.pkg); got != tt.want {
+				t.Errorf("sourcePackageName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPackageSource tests that distro packages named after their source have that source
+func TestPackageSource(t *testing.T) {
+	tests := []struct {
+		name string
+		pkg  pkg.Package
+		want string
+	}{
+		{"dpkg source", pkg.Package{Name: "libssl3", Type: pkg.DebPkg, Metadata: pkg.DpkgDBEntry{Source: "openssl"}}, "openssl"},
+		{"dpkg source is the package", pkg.Package{Name: "openssl", Type: pkg.DebPkg, Metadata: pkg.DpkgDBEntry{Source: "openssl"}}, "openssl"},
+		{"dpkg without source", pkg.Package{Name: "nginx", Type: pkg.DebPkg, Metadata: pkg.DpkgDBEntry{}}, "nginx"},
+		{"language package", pkg.Package{Name: "express", Type: pkg.NpmPkg}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := packageSource(tt.pkg); got != tt.want {
+				t.Errorf("packageSource() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSourceRPMName tests extracting the name from source RPM file names
+func TestSourceRPMName(t *testing.T) {
+	tests := map[string]string{
+		"openssl-3.0.7-25.el9.src.rpm":             "openssl",
+		"java-17-openjdk-17.0.9.0.9-2.el9.src.rpm": "java-17-openjdk",
+		"glibc-2.34-60.el9.nosrc.rpm":              "glibc",
+		"openssl-3.0.7":                            "",
+		"broken.src.rpm":                           "",
+		"":                                         "",
+	}
+
+	for input, want := range tests {
+		if got := sourceRPMName(input); got != want {
+			t.Errorf("sourceRPMName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// TestCollapseBySource tests that binaries from the same source are reported once
+func TestCollapseBySource(t *testing.T) {
+	results := []ComponentResult{
+		{Name: "libssl3", Version: "3.0.11-1", Type: "deb", SourcePackage: "openssl", MatchedProduct: "openssl", MatchedCycle: "3.0",
+			Locations: []string{"/var/lib/dpkg/info/libssl3.list", "/var/lib/dpkg/status"}},
+		{Name: "nginx", Version: "1.22.1-9", Type: "deb", SourcePackage: "nginx", MatchedProduct: "nginx", MatchedCycle: "1.22"},
+		{Name: "express", Version: "4.18.2", Type: "npm", MatchedProduct: "express", MatchedCycle: "4"},
+		{Name: "openssl", Version: "3.0.11-1", Type: "deb", SourcePackage: "openssl", MatchedProduct: "openssl", MatchedCycle: "3.0"},
+		{Name: "libssl-dev", Version: "3.0.11-1", Type: "deb", SourcePackage: "openssl", MatchedProduct: "openssl", MatchedCycle: "3.0",
+			Locations: []string{"/var/lib/dpkg/info/libssl-dev.list", "/var/lib/dpkg/status"}},
+		{Name: "libssl1.1", Version: "1.1.1n-0", Type: "deb", SourcePackage: "openssl", MatchedProduct: "openssl", MatchedCycle: "1.1.1"},
+		{Name: "libfoo1", Version: "1.0", Type: "deb", SourcePackage: "foo"},
+		{Name: "libfoo2", Version: "1.0", Type: "deb", SourcePackage: "foo"},
+	}
+
+	got := collapseBySource(results)
+
+	wantNames := []string{"openssl", "nginx", "express", "libssl1.1", "libfoo1", "libfoo2"}
+	if len(got) != len(wantNames) {
+		t.Fatalf("collapseBySource() returned %d results, want %d", len(got), len(wantNames))
+	}
+	for i, name := range wantNames {
+		if got[i].Name != name {
+			t.Errorf("result %d name = %q, want %q", i, got[i].Name, name)
+		}
+	}
+
+	if want := []string{"libssl3", "openssl", "libssl-dev"}; !slices.Equal(got[0].Binaries, want) {
+		t.Errorf("collapsed binaries = %v, want %v", got[0].Binaries, want)
+	}
+	if want := []string{"/var/lib/dpkg/info/libssl3.list", "/var/lib/dpkg/status", "/var/lib/dpkg/info/libssl-dev.list"}; !slices.Equal(got[0].Locations, want) {
+		t.Errorf("collapsed locations = %v, want %v", got[0].Locations, want)
+	}
+	if want := []string{"libssl1.1"}; !slices.Equal(got[3].Binaries, want) {
+		t.Errorf("single binary = %v, want %v", got[3].Binaries, want)
+	}
+	if want := []string{"nginx"}; !slices.Equal(got[1].Binaries, want) {
+		t.Errorf("binary named after its source = %v, want %v", got[1].Binaries, want)
+	}
+	if got[2].Binaries != nil || got[4].Binaries != nil {
+		t.Error("components without a matched source package should not list binaries")
+	}
+}
+
+// TestMatchStepsSourcePackage tests that source package lookups follow the binary's distro PURLs
+func TestMatchStepsSourcePackage(t *testing.T) {
+	scanner := &Scanner{config: DefaultScannerConfig()}
+
+	steps := scanner.matchSteps(pkg.Package{
+		Name:     "libssl3",
+		Version:  "3.0.11-1~deb12u2",
+		Type:     pkg.DebPkg,
+		Metadata: pkg.DpkgDBEntry{Package: "libssl3", Source: "openssl"},
+	})
+
+	var queries []string
+	for _, step := range steps {
+		if step.Method == MatchSourcePackage {
+			queries = append(queries, step.Query)
+		}
+	}
+	if want := []string{"pkg:deb/debian/openssl", "pkg:deb/ubuntu/openssl", "openssl"}; !slices.Equal(queries, want) {
+		t.Errorf("source package queries = %v, want %v", queries, want)
+	}
+
+	// A versioned source package name gives the cycle of its binaries
+	steps = scanner.matchSteps(pkg.Package{
+		Name:     "libpq5",
+		Version:  "15.5-0+deb12u1",
+		Type:     pkg.DebPkg,
+		Metadata: pkg.DpkgDBEntry{Package: "libpq5", Source: "postgresql-15"},
+	})
+	for _, step := range steps {
+		if step.Method == MatchVersionedName && step.Query != "postgresql 15" {
+			t.Errorf("versioned name query = %q, want %q", step.Query, "postgresql 15")
+		}
+	}
+}
+
+// TestRuntimeProduct tests mapping binary-cataloger runtimes and Go stdlib to products
+func TestRuntimeProduct(t *testing.T) {
+	tests := []struct {
+		name        string
+		pkg         pkg.Package
+		wantProduct string
+		wantVersion string
+	}{
+		{"python binary", pkg.Package{Name: "python", Version: "3.11.4", Type: pkg.BinaryPkg}, "python", "3.11.4"},
+		{"node binary", pkg.Package{Name: "node", Version: "18.19.0", Type: pkg.BinaryPkg}, "nodejs", "18.19.0"},
+		{"httpd binary", pkg.Package{Name: "httpd", Version: "2.4.58", Type: pkg.BinaryPkg}, "apache-http-server", "2.4.58"},
+		{"redis binary", pkg.Package{Name: "redis", Version: "7.2.3", Type: pkg.BinaryPkg}, "redis", "7.2.3"},
+		{"postgres binary", pkg.Package{Name: "postgresql", Version: "15.5", Type: pkg.BinaryPkg}, "postgresql", "15.5"},
+		{"go binary", pkg.Package{Name: "go", Version: "go1.21.5", Type: pkg.BinaryPkg}, "go", "1.21.5"},
+		{"legacy java version", pkg.Package{Name: "openjdk", Version: "1.8.0_392-b08", Type: pkg.BinaryPkg}, "eclipse-temurin", "8.0.392"},
+		{"modern java version", pkg.Package{Name: "jre", Version: "17.0.9+9", Type: pkg.BinaryPkg}, "eclipse-temurin", "17.0.9+9"},
+		{"legacy zulu version", pkg.Package{Name: "zulu", Version: "1.8.0_392", Type: pkg.BinaryPkg}, "azul-zulu", "8.0.392"},
+		{"non-java version left alone", pkg.Package{Name: "busybox", Version: "1.36_1", Type: pkg.BinaryPkg}, "busybox", "1.36_1"},
+		{"go stdlib", pkg.Package{Name: "stdlib", Version: "go1.21.5", Type: pkg.GoModulePkg}, "go", "1.21.5"},
+		{"go stdlib with experiment", pkg.Package{Name: "stdlib", Version: "go1.22.0 X:boringcrypto", Type: pkg.GoModulePkg}, "go", "1.22.0"},
+		{"unknown binary", pkg.Package{Name: "jq", Version: "1.7", Type: pkg.BinaryPkg}, "", ""},
+		{"python distro package", pkg.Package{Name: "python", Version: "3.11.4", Type: pkg.DebPkg}, "", ""},
+		{"go module", pkg.Package{Name: "golang.org/x/net", Version: "v0.17.0", Type: pkg.GoModulePkg}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product, version, ok := runtimeProduct(tt.pkg)
+			if ok != (tt.wantProduct != "") || product != tt.wantProduct || version != tt.wantVersion {
+				t.Errorf("runtimeProduct() = %q, %q, %v, want %q, %q", product, version, ok, tt.wantProduct, tt.wantVersion)
+			}
+		})
+	}
+}
+
+// TestJavaVersion tests converting legacy Java versions
+func TestJavaVersion(t *testing.T) {
+	tests := map[string]string{
+		"1.8.0_392-b08": "8.0.392",
+		"1.8.0_392":     "8.0.392",
+		"1.7.0_80":      "7.0.80",
+		"17.0.9+9":      "17.0.9+9",
+		"1.8.0":         "1.8.0",
+		"21":            "21",
+	}
+
+	for input, want := range tests {
+		if got := javaVersion(input); got != want {
+			t.Errorf("javaVersion(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// TestMatchStepsRuntime tests that Go stdlib is evaluated as the go product with a plain version
+func TestMatchStepsRuntime(t *testing.T) {
+	scanner := &Scanner{config: DefaultScannerConfig()}
+
+	steps := scanner.matchSteps(pkg.Package{
+		Name:    "stdlib",
+		Version: "go1.21.5",
+		Type:    pkg.GoModulePkg,
+		PURL:    "pkg:golang/stdlib@1.21.5",
+	})
+
+	step := steps[1]
+	if step.Method != MatchRuntime || step.Lookup == nil {
+		t.Fatalf("second step = %s (skipped %v), want an active runtime step", step.Method, step.Lookup == nil)
+	}
+	if step.Query != "go 1.21.5" || step.Version != "1.21.5" {
+		t.Errorf("step = %q version %q, want %q version %q", step.Query, step.Version, "go 1.21.5", "1.21.5")
+	}
+}
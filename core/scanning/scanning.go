@@ -2,8 +2,10 @@ package scanning
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,66 +23,192 @@ const (
 	StatusActive         EOLStatus = "active"
 	StatusEOL            EOLStatus = "eol"
 	StatusEOLSoon        EOLStatus = "eol_soon"
+	StatusSecurityOnly   EOLStatus = "security_only"
 	StatusUnknown        EOLStatus = "unknown"
-	DefaultDBMaxAge                = 7 * 24 * time.Hour// 1 week
+	StatusRolling        EOLStatus = "rolling"          // Rolling or testing OS release without an end of life
+	DefaultDBMaxAge                = 7 * 24 * time.Hour // 1 week
 	DefaultForwardLookup           = 90                 // 90 days default forward lookup
 )
 
 // ComponentResult represents the scan result for a single component
 type ComponentResult struct {
-	Name           string    `json:"name"`
-	Version        string    `json:"version"`
-	PURL           string    `json:"purl"`
-	Type           string    `json:"type"`
-	Status         EOLStatus `json:"status"`
-	EOLDate        string    `json:"eol_date,omitempty"`
-	DaysUntilEOL   *int      `json:"days_until_eol,omitempty"`
-	MatchedProduct string    `json:"matched_product,omitempty"`
-	MatchedCycle   string    `json:"matched_cycle,omitempty"`
-	LatestVersion  string    `json:"latest_version,omitempty"`
-	IsLTS          bool      `json:"is_lts"`
+	Name                        string           `json:"name"`
+	Version                     string           `json:"version"`
+	PURL                        string           `json:"purl"`
+	Type                        string           `json:"type"`
+	Status                      EOLStatus        `json:"status"`
+	EOLDate                     string           `json:"eol_date,omitempty"`
+	DaysUntilEOL                *int             `json:"days_until_eol,omitempty"`
+	SupportEndDate              string           `json:"support_end_date,omitempty"`
+	DaysUntilSupportEnd         *int             `json:"days_until_support_end,omitempty"`
+	ExtendedSupportEndDate      string           `json:"extended_support_end_date,omitempty"`
+	DaysUntilExtendedSupportEnd *int             `json:"days_until_extended_support_end,omitempty"`
+	HasExtendedSupport          bool             `json:"has_extended_support,omitempty"`
+	ExtendedSupportApplied      bool             `json:"extended_support_applied,omitempty"`
+	DiscontinuedDate            string           `json:"discontinued_date,omitempty"`
+	IsDiscontinued              bool             `json:"is_discontinued,omitempty"`
+	MatchedProduct              string           `json:"matched_product,omitempty"`
+	Match                       *MatchInfo       `json:"match,omitempty"`
+	MatchedCycle                string           `json:"matched_cycle,omitempty"`
+	AmbiguousCycles             []string         `json:"ambiguous_cycles,omitempty"`
+	CycleFallback               bool             `json:"cycle_fallback,omitempty"` // No cycle matched the version; the closest cycle of its major was taken
+	LatestVersion               string           `json:"latest_version,omitempty"`
+	LatestReleaseDate           string           `json:"latest_release_date,omitempty"`
+	OutdatedInCycle             bool             `json:"outdated_in_cycle,omitempty"`
+	ReleasesBehind              *int             `json:"releases_behind,omitempty"`
+	DaysSinceLatestRelease      *int             `json:"days_since_latest_release,omitempty"`
+	IsLTS                       bool             `json:"is_lts"`
+	Recommendations             []Recommendation `json:"recommendation,omitempty"`
+	SourcePackage               string           `json:"source_package,omitempty"`
+	Vendor                      string           `json:"vendor,omitempty"`            // Distributor of a runtime (e.g. the JDK implementor)
+	Binaries                    []string         `json:"binaries,omitempty"`          // Binary packages, Go binaries or framework directories covered by this finding
+	Locations                   []string         `json:"locations,omitempty"`         // Paths syft found the component at
+	LayerDigest                 string           `json:"layer_digest,omitempty"`      // Image layer that introduced the component
+	LayerInstruction            string           `json:"layer_instruction,omitempty"` // Dockerfile instruction that created the layer
+	LayerSource                 LayerSource      `json:"layer_source,omitempty"`      // Whether the layer belongs to the base image or the application
+	OriginRelease               *OriginRelease   `json:"origin_release,omitempty"`    // Older distro release the package was built for
+	Upstream                    *SupportWindow   `json:"upstream,omitempty"`          // Upstream lifecycle, when the status follows distro support
+	DistroSupport               *SupportWindow   `json:"distro_support,omitempty"`    // Lifecycle of the distro release shipping the package
+	Suggestions                 []Suggestion     `json:"suggestions,omitempty"`       // Products resembling the component when none matched
+}
+
+// MatchMethod describes how a component was matched to a product
+type MatchMethod string
+
+const (
+	MatchExactPURL     MatchMethod = "exact_purl"
+	MatchPURLPrefix    MatchMethod = "purl_prefix"
+	MatchDistroPURL    MatchMethod = "distro_purl"
+	MatchCPE           MatchMethod = "cpe"
+	MatchName          MatchMethod = "name"
+	MatchAlias         MatchMethod = "alias"
+	MatchRepology      MatchMethod = "repology"
+	MatchOSRelease     MatchMethod = "os_release"
+	MatchUserMapping   MatchMethod = "user_mapping"
+	MatchVersionedName MatchMethod = "versioned_name"
+	MatchSourcePackage MatchMethod = "source_package"
+	MatchRuntime       MatchMethod = "runtime"
+	MatchMaven         MatchMethod = "maven_coordinates"
+	MatchInferredOS    MatchMethod = "inferred_os"
+)
+
+// MatchConfidence indicates how likely a match is to be correct
+type MatchConfidence string
+
+const (
+	ConfidenceHigh   MatchConfidence = "high"
+	ConfidenceMedium MatchConfidence = "medium"
+	ConfidenceLow    MatchConfidence = "low"
+)
+
+// MatchInfo records how a component was matched to its product
+type MatchInfo struct {
+	Method       MatchMethod        `json:"method"`
+	Identifier   string             `json:"identifier"`
+	Confidence   MatchConfidence    `json:"confidence"`
+	Alternatives []MatchAlternative `json:"alternatives,omitempty"` // Other products the lookup matched, best ranked first
+	Ambiguous    bool               `json:"ambiguous,omitempty"`    // An alternative ranked as high as the matched product
+}
+
+// MatchAlternative is another product the lookup that matched a component found
+type MatchAlternative struct {
+	Product    string `json:"product"`
+	Identifier string `json:"identifier"`
+}
+
+// RecommendationStrategy describes how an upgrade target was chosen
+type RecommendationStrategy string
+
+const (
+	StrategyNewestLTS         RecommendationStrategy = "newest_lts"
+	StrategyNearestMaintained RecommendationStrategy = "nearest_maintained"
+)
+
+// Recommendation is a suggested upgrade target for an EOL or EOL-soon component
+type Recommendation struct {
+	Strategy     RecommendationStrategy `json:"strategy"`
+	Cycle        string                 `json:"cycle"`
+	Version      string                 `json:"version,omitempty"`
+	EOLDate      string                 `json:"eol_date,omitempty"`
+	DaysUntilEOL *int                   `json:"days_until_eol,omitempty"`
+	IsLTS        bool                   `json:"is_lts"`
 }
 
 // OSInfo represents the operating system EOL information
 type OSInfo struct {
-	Name           string    `json:"name"`
-	ID             string    `json:"id"`
-	Version        string    `json:"version"`
-	VersionID      string    `json:"version_id"`
-	PrettyName     string    `json:"pretty_name"`
-	Status         EOLStatus `json:"status"`
-	EOLDate        string    `json:"eol_date,omitempty"`
-	DaysUntilEOL   *int      `json:"days_until_eol,omitempty"`
-	MatchedProduct string    `json:"matched_product,omitempty"`
-	MatchedCycle   string    `json:"matched_cycle,omitempty"`
-	IsLTS          bool      `json:"is_lts"`
+	Name                        string           `json:"name"`
+	ID                          string           `json:"id"`
+	Version                     string           `json:"version"`
+	VersionID                   string           `json:"version_id"`
+	PrettyName                  string           `json:"pretty_name"`
+	Status                      EOLStatus        `json:"status"`
+	EOLDate                     string           `json:"eol_date,omitempty"`
+	DaysUntilEOL                *int             `json:"days_until_eol,omitempty"`
+	SupportEndDate              string           `json:"support_end_date,omitempty"`
+	DaysUntilSupportEnd         *int             `json:"days_until_support_end,omitempty"`
+	ExtendedSupportEndDate      string           `json:"extended_support_end_date,omitempty"`
+	DaysUntilExtendedSupportEnd *int             `json:"days_until_extended_support_end,omitempty"`
+	HasExtendedSupport          bool             `json:"has_extended_support,omitempty"`
+	ExtendedSupportApplied      bool             `json:"extended_support_applied,omitempty"`
+	DiscontinuedDate            string           `json:"discontinued_date,omitempty"`
+	IsDiscontinued              bool             `json:"is_discontinued,omitempty"`
+	MatchedProduct              string           `json:"matched_product,omitempty"`
+	Match                       *MatchInfo       `json:"match,omitempty"`
+	MatchedCycle                string           `json:"matched_cycle,omitempty"`
+	AmbiguousCycles             []string         `json:"ambiguous_cycles,omitempty"`
+	CycleFallback               bool             `json:"cycle_fallback,omitempty"` // No cycle matched the version; the closest cycle of its major was taken
+	IsLTS                       bool             `json:"is_lts"`
+	Recommendations             []Recommendation `json:"recommendation,omitempty"`
+	Inferred                    bool             `json:"inferred,omitempty"` // No os-release; the distro was inferred from other evidence
 }
 
 // ScanSummary contains the overall scan results
 type ScanSummary struct {
-	TotalComponents   int               `json:"total_components"`
-	EOLComponents     int               `json:"eol_components"`
-	EOLSoonComponents int               `json:"eol_soon_components"`
-	ActiveComponents  int               `json:"active_components"`
-	UnknownComponents int               `json:"unknown_components"`
-	Components        []ComponentResult `json:"components"`
-	OS                *OSInfo           `json:"os,omitempty"`
-	ScanTime          time.Time         `json:"scan_time"`
-	ImageReference    string            `json:"image_reference"`
-	DBLastUpdated     string            `json:"db_last_updated"`
-	ForwardLookupDays int               `json:"forward_lookup_days"`
+	TotalComponents           int               `json:"total_components"`
+	EOLComponents             int               `json:"eol_components"`
+	EOLSoonComponents         int               `json:"eol_soon_components"`
+	SecurityOnlyComponents    int               `json:"security_only_components"`
+	ActiveComponents          int               `json:"active_components"`
+	RollingComponents         int               `json:"rolling_components"` // Rolling or testing releases without an end of life
+	UnknownComponents         int               `json:"unknown_components"`
+	OutdatedInCycleComponents int               `json:"outdated_in_cycle_components"`
+	MixedReleaseComponents    int               `json:"mixed_release_components"`    // Packages built for an older distro release
+	DistroSupportedComponents int               `json:"distro_supported_components"` // Upstream EOL packages still supported by their distro release
+	TotalFindings             int               `json:"total_findings"`              // Distinct product-cycle findings
+	EOLFindings               int               `json:"eol_findings"`
+	EOLSoonFindings           int               `json:"eol_soon_findings"`
+	SecurityOnlyFindings      int               `json:"security_only_findings"`
+	ActiveFindings            int               `json:"active_findings"`
+	RollingFindings           int               `json:"rolling_findings"`
+	Components                []ComponentResult `json:"components"`
+	Findings                  []Finding         `json:"findings"` // Components grouped by product and cycle
+	OS                        *OSInfo           `json:"os,omitempty"`
+	ScanTime                  time.Time         `json:"scan_time"`
+	ImageReference            string            `json:"image_reference"`
+	DBLastUpdated             string            `json:"db_last_updated"`
+	ForwardLookupDays         int               `json:"forward_lookup_days"`
+	ExtendedSupport           bool              `json:"extended_support"`
+	DistroSupport             bool              `json:"distro_support"`
+	SuggestionsUnavailable    bool              `json:"suggestions_unavailable,omitempty"` // No index to suggest products for unknown components from
 }
 
 // ScannerConfig holds configuration for the scanner
 type ScannerConfig struct {
-	DBPath            string                        // Custom DB path (empty for default)
-	DBMaxAge          time.Duration                 // Max age before DB refresh
-	ForwardLookupDays int                           // Days to look ahead for upcoming EOL
-	AutoUpdateDB      bool                          // Automatically update DB if stale
-	Categories        []string                      // Categories to sync
-	RegistryAuth      *sbomgen.RegistryCredentials  // Registry credentials
-	RegistryCAFileOrDir string                      // Custom CA certificate file or directory
-	ProgressCallback  func(stage, message string)   // Progress callback
+	DBPath              string                       // Custom DB path (empty for default)
+	DBMaxAge            time.Duration                // Max age before DB refresh
+	ForwardLookupDays   int                          // Days to look ahead for upcoming EOL
+	AutoUpdateDB        bool                         // Automatically update DB if stale
+	Categories          []string                     // Categories to sync
+	RegistryAuth        *sbomgen.RegistryCredentials // Registry credentials
+	RegistryCAFileOrDir string                       // Custom CA certificate file or directory
+	ExtendedSupport     bool                         // Treat cycles as supported until extended support ends (ESM, ELS, etc.)
+	DistroSupport       bool                         // Evaluate distro packages against the support of their distro release
+	MappingFile         string                       // User package-to-product mapping file (YAML or JSON)
+	Mappings            *MappingConfig               // User mappings (loaded from MappingFile if nil)
+	Matchers            []Matcher                    // Matching chain, in order (DefaultMatchers() if nil)
+	DisabledMatchers    []string                     // Names of matchers to leave out of the chain
+	SuggestProducts     bool                         // Suggest products for components no lookup matched
+	ProgressCallback    func(stage, message string)  // Progress callback
 }
 
 // DefaultScannerConfig returns the default scanner configuration
@@ -97,7 +225,11 @@ func DefaultScannerConfig() *ScannerConfig {
 type Scanner struct {
 	config    *ScannerConfig
 	dbManager *db.EOLDatabaseManager
+	lookup    db.ProductLookup
 	generator *sbomgen.Generator
+	mappings  *MappingConfig
+	matchers  []Matcher
+	trace     *matchTrace // Lookups of the scan being explained, if any
 }
 
 // NewScanner creates a new Scanner with the given configuration
@@ -107,11 +239,28 @@ func NewScanner(config *ScannerConfig) (*Scanner, error) {
 	}
 
 	scanner := &Scanner{
-		config: config,
+		config:   config,
+		mappings: config.Mappings,
+	}
+
+	// Load user mappings
+	if scanner.mappings == nil && config.MappingFile != "" {
+		mappings, err := LoadMappings(config.MappingFile)
+		if err != nil {
+			return nil, err
+		}
+		scanner.mappings = mappings
+	}
+
+	// Build the matching chain
+	matchers, err := matcherChain(config)
+	if err != nil {
+		return nil, err
 	}
+	scanner.matchers = matchers
 
-	// Initialize SBOM generator
-	generator := sbomgen.NewGenerator()
+	// Initialize SBOM generator; distro files are recorded for images without an os-release
+	generator := sbomgen.NewGenerator().WithFileContents(distroEvidenceFiles...)
 	if config.RegistryAuth != nil {
 		cred := config.RegistryAuth
 		if cred.Username != "" {
@@ -181,7 +330,10 @@ func (s *Scanner) ensureDatabase(ctx context.Context) error {
 	// If DB doesn't exist or auto-update is enabled, check if we need to sync
 	if !dbExists {
 		s.progress("db", "Database not found, performing initial sync...")
-		return s.syncDatabase(ctx)
+		if err := s.syncDatabase(ctx); err != nil {
+			return err
+		}
+		return s.checkMappings()
 	}
 
 	if s.config.AutoUpdateDB {
@@ -191,11 +343,22 @@ func (s *Scanner) ensureDatabase(ctx context.Context) error {
 		}
 		if needsUpdate {
 			s.progress("db", "Database is stale, updating...")
-			return s.syncDatabase(ctx)
+			if err := s.syncDatabase(ctx); err != nil {
+				return err
+			}
+			return s.checkMappings()
 		}
 	}
 
 	s.progress("db", "Database is up-to-date")
+	return s.checkMappings()
+}
+
+// checkMappings fails on user mappings to products the database does not have
+func (s *Scanner) checkMappings() error {
+	if err := s.mappings.checkProducts(s.lookups()); err != nil {
+		return fmt.Errorf("invalid mappings: %w", err)
+	}
 	return nil
 }
 
@@ -225,9 +388,29 @@ func (s *Scanner) syncDatabase(ctx context.Context) error {
 	s.progress("db", fmt.Sprintf("Synced %d products, %d cycles, %d identifiers",
 		result.ProductsProcessed, result.CyclesProcessed, result.IdentifiersProcessed))
 
+	// Reload the index from the synced database on the next lookup
+	s.lookup = nil
+
 	return nil
 }
 
+// lookups returns where products are looked up: an in-memory index of the database,
+// loaded on the first lookup of the Scanner, or the database itself if the index
+// cannot be loaded
+func (s *Scanner) lookups() db.ProductLookup {
+	if s.lookup != nil {
+		return s.lookup
+	}
+	index, err := s.dbManager.LoadIndex()
+	if err != nil {
+		s.progress("db", fmt.Sprintf("Failed to load EOL index, querying database: %v", err))
+		s.lookup = s.dbManager
+		return s.lookup
+	}
+	s.lookup = index
+	return s.lookup
+}
+
 // ScanFromTar scans a container image from a tar archive
 func (s *Scanner) ScanFromTar(ctx context.Context, tarPath string) (*ScanSummary, error) {
 	if err := s.ensureDatabase(ctx); err != nil {
@@ -273,6 +456,15 @@ func (s *Scanner) ScanFromDocker(ctx context.Context, imageRef string) (*ScanSum
 	return s.analyzeSBOM(sbomResult, imageRef)
 }
 
+// ScanSBOM scans an SBOM generated elsewhere; source names it in the summary
+func (s *Scanner) ScanSBOM(ctx context.Context, sbomResult *sbom.SBOM, source string) (*ScanSummary, error) {
+	if err := s.ensureDatabase(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.analyzeSBOM(sbomResult, source)
+}
+
 // analyzeSBOM analyzes the SBOM and checks components against EOL database
 func (s *Scanner) analyzeSBOM(sbomResult *sbom.SBOM, imageRef string) (*ScanSummary, error) {
 	s.progress("analyze", "Analyzing components for EOL status...")
@@ -281,7 +473,10 @@ func (s *Scanner) analyzeSBOM(sbomResult *sbom.SBOM, imageRef string) (*ScanSumm
 		ScanTime:          time.Now(),
 		ImageReference:    imageRef,
 		ForwardLookupDays: s.config.ForwardLookupDays,
+		ExtendedSupport:   s.config.ExtendedSupport,
+		DistroSupport:     s.config.DistroSupport,
 		Components:        make([]ComponentResult, 0),
+		Findings:          make([]Finding, 0),
 	}
 
 	// Get DB last updated time
@@ -291,163 +486,308 @@ func (s *Scanner) analyzeSBOM(sbomResult *sbom.SBOM, imageRef string) (*ScanSumm
 	}
 
 	// Check OS/Distribution EOL status and add as first component
-	if sbomResult.Artifacts.LinuxDistribution != nil {
-		osInfo := s.checkOSEOL(sbomResult.Artifacts.LinuxDistribution)
-		if osInfo != nil {
-			summary.OS = osInfo
-			// Add OS as a component
-			osComponent := ComponentResult{
-				Name:           osInfo.PrettyName,
-				Version:        osInfo.VersionID,
-				Type:           "os",
-				Status:         osInfo.Status,
-				EOLDate:        osInfo.EOLDate,
-				DaysUntilEOL:   osInfo.DaysUntilEOL,
-				MatchedProduct: osInfo.MatchedProduct,
-				MatchedCycle:   osInfo.MatchedCycle,
-				IsLTS:          osInfo.IsLTS,
-			}
-			if osComponent.Name == "" {
-				osComponent.Name = fmt.Sprintf("%s %s", osInfo.Name, osInfo.Version)
-			}
-			if osComponent.Version == "" {
-				osComponent.Version = osInfo.Version
-			}
-			summary.Components = append(summary.Components, osComponent)
-			summary.TotalComponents++
-			switch osComponent.Status {
-			case StatusEOL:
-				summary.EOLComponents++
-			case StatusEOLSoon:
-				summary.EOLSoonComponents++
-			case StatusActive:
-				summary.ActiveComponents++
-			case StatusUnknown:
-				summary.UnknownComponents++
-			}
+	osInfo := s.checkOSEOL(sbomResult.Artifacts.LinuxDistribution)
+	if osInfo == nil {
+		// Images without an os-release (distroless, Chainguard) have their distro inferred
+		osInfo = s.checkInferredOS(inferDistro(sbomResult))
+	}
+	if osInfo != nil {
+		summary.OS = osInfo
+		// Add OS as a component
+		osComponent := ComponentResult{
+			Name:                        osInfo.PrettyName,
+			Version:                     osInfo.VersionID,
+			Type:                        "os",
+			Status:                      osInfo.Status,
+			EOLDate:                     osInfo.EOLDate,
+			DaysUntilEOL:                osInfo.DaysUntilEOL,
+			SupportEndDate:              osInfo.SupportEndDate,
+			DaysUntilSupportEnd:         osInfo.DaysUntilSupportEnd,
+			ExtendedSupportEndDate:      osInfo.ExtendedSupportEndDate,
+			DaysUntilExtendedSupportEnd: osInfo.DaysUntilExtendedSupportEnd,
+			HasExtendedSupport:          osInfo.HasExtendedSupport,
+			ExtendedSupportApplied:      osInfo.ExtendedSupportApplied,
+			DiscontinuedDate:            osInfo.DiscontinuedDate,
+			IsDiscontinued:              osInfo.IsDiscontinued,
+			MatchedProduct:              osInfo.MatchedProduct,
+			Match:                       osInfo.Match,
+			MatchedCycle:                osInfo.MatchedCycle,
+			AmbiguousCycles:             osInfo.AmbiguousCycles,
+			CycleFallback:               osInfo.CycleFallback,
+			IsLTS:                       osInfo.IsLTS,
+			Recommendations:             osInfo.Recommendations,
+		}
+		if osComponent.Name == "" {
+			osComponent.Name = fmt.Sprintf("%s %s", osInfo.Name, osInfo.Version)
+		}
+		if osComponent.Version == "" {
+			osComponent.Version = osInfo.Version
 		}
+		summary.addComponent(osComponent)
 	}
 
 	// Extract packages from SBOM
 	packages := sbomResult.Artifacts.Packages.Sorted()
 
+	// Go binaries are reported through the toolchain that built them, which
+	// replaces the stdlib entries syft derives from each binary
+	toolchains := goToolchains(packages)
+
+	// Assemblies of installed .NET shared frameworks are reported as their runtime
+	frameworks := dotnetFrameworks(packages)
+
+	// Components are attributed to the image layer that introduced them
+	layers := newImageLayers(sbomResult.Source)
+
+	// Packages built for an older release of the OS are flagged with that release
+	origins := s.newReleaseOrigins(summary.OS)
+
+	// Distro packages can follow the support of their distro release instead of upstream
+	support := s.newDistroSupport(summary.OS)
+
+	results := make([]ComponentResult, 0, len(packages)+len(toolchains)+len(frameworks))
 	for _, p := range packages {
+		if len(toolchains) > 0 && isGoStdlib(p) {
+			continue
+		}
+		if _, _, _, ok := sharedFrameworkOf(p); ok {
+			continue
+		}
+		result := s.checkComponent(p)
+		layers.attribute(&result, p)
+		origins.check(&result, p)
+		support.apply(&result, p)
+		results = append(results, result)
+	}
+	for _, toolchain := range toolchains {
+		p := toolchain.pkg()
+		result := s.checkComponent(p)
+		result.Binaries = toolchain.binaries
+		layers.attribute(&result, p)
+		results = append(results, result)
+	}
+	for _, framework := range frameworks {
+		p := framework.pkg()
 		result := s.checkComponent(p)
-		summary.Components = append(summary.Components, result)
+		result.Binaries = framework.dirs
+		result.Locations = framework.dirs
+		layers.attribute(&result, p)
+		results = append(results, result)
+	}
 
-		// Update counts
-		summary.TotalComponents++
-		switch result.Status {
-		case StatusEOL:
-			summary.EOLComponents++
-		case StatusEOLSoon:
-			summary.EOLSoonComponents++
-		case StatusActive:
-			summary.ActiveComponents++
-		case StatusUnknown:
-			summary.UnknownComponents++
-		}
+	// Suggestions come from the in-memory index, which may have failed to load
+	if _, ok := s.lookups().(*db.Index); s.config.SuggestProducts && !ok {
+		summary.SuggestionsUnavailable = true
+	}
+
+	// Binaries built from the same source package are reported once, but counted as
+	// the packages they are
+	for _, result := range results {
+		summary.countComponent(result)
+	}
+	summary.Components = append(summary.Components, collapseBySource(results)...)
+
+	// Packages of the same product and cycle are also reported as one finding
+	for _, finding := range GroupFindings(summary.Components) {
+		summary.addFinding(finding)
 	}
 
-	s.progress("done", fmt.Sprintf("Scan complete: %d total, %d EOL, %d EOL soon",
-		summary.TotalComponents, summary.EOLComponents, summary.EOLSoonComponents))
+	s.progress("done", fmt.Sprintf("Scan complete: %d total, %d EOL, %d EOL soon (%d distinct EOL findings)",
+		summary.TotalComponents, summary.EOLComponents, summary.EOLSoonComponents, summary.EOLFindings))
 
 	return summary, nil
 }
 
-// checkComponent checks a single component against the EOL database
+// checkComponent checks a single component against the EOL database. The first step of
+// the lookup chain that finds a product wins; while a scan is explained, the rest of
+// the chain is walked too and every attempt is traced, so shadowed matches are visible.
 func (s *Scanner) checkComponent(p pkg.Package) ComponentResult {
-	result := ComponentResult{
-		Name:    p.Name,
-		Version: p.Version,
-		Type:    string(p.Type),
-		Status:  StatusUnknown,
-	}
+	result := newComponentResult(p)
+	matched := false
 
-	// Get PURL if available
-	if p.PURL != "" {
-		result.PURL = p.PURL
-	}
-
-	// Try to look up by exact PURL first
-	if result.PURL != "" {
-		product, cycles, _, err := s.dbManager.LookupByPURL(result.PURL)
-		if err == nil && product != nil {
-			result.MatchedProduct = product.Name
-			result = s.evaluateEOLStatus(result, cycles, p.Version)
-			return result
+	var attempts []MatchAttempt
+	for _, step := range s.matchSteps(p) {
+		if matched && s.trace == nil {
+			break
 		}
-	}
+		attempt := MatchAttempt{Matcher: step.matcher, Method: step.Method, Query: step.Query}
 
-	// For deb/rpm packages, try distro-specific PURL lookup
-	// Database has entries like pkg:deb/debian/nginx, pkg:deb/ubuntu/python3.12
-	if string(p.Type) == "deb" {
-		for _, distro := range []string{"debian", "ubuntu"} {
-			product, cycles, err := s.dbManager.LookupByPURLPrefix("deb/"+distro, p.Name)
-			if err == nil && product != nil {
-				result.MatchedProduct = product.Name
-				result = s.evaluateEOLStatus(result, cycles, p.Version)
-				return result
-			}
+		if step.Lookup == nil {
+			attempt.Reason = "skipped: " + step.Skip
+			attempts = append(attempts, attempt)
+			continue
 		}
-	} else if string(p.Type) == "rpm" {
-		for _, distro := range []string{"fedora", "redhat", "centos", "amzn"} {
-			product, cycles, err := s.dbManager.LookupByPURLPrefix("rpm/"+distro, p.Name)
-			if err == nil && product != nil {
-				result.MatchedProduct = product.Name
-				result = s.evaluateEOLStatus(result, cycles, p.Version)
-				return result
+
+		candidate, err := step.Lookup()
+		switch {
+		case err != nil:
+			attempt.Reason = "lookup failed: " + err.Error()
+		case candidate == nil && step.Miss != "":
+			attempt.Reason = step.Miss
+		case candidate == nil:
+			attempt.Reason = "no identifier matched"
+		default:
+			stepResult := s.applyMatch(newComponentResult(p), p, step.matchStep, candidate)
+			info := stepResult.Match
+			attempt.Matched = true
+			attempt.Method = info.Method
+			attempt.Product = candidate.Product.Name
+			attempt.Identifier = info.Identifier
+			attempt.Confidence = info.Confidence
+			attempt.Alternatives = info.Alternatives
+
+			if matched {
+				attempt.Reason = "matched, but an earlier lookup already selected a product"
+			} else {
+				matched = true
+				attempt.Selected = true
+				attempt.Reason = fmt.Sprintf("matched %s with %s confidence", candidate.Product.Name, info.Confidence)
+				result = stepResult
 			}
 		}
-	} else if string(p.Type) == "apk" {
-		product, cycles, err := s.dbManager.LookupByPURLPrefix("apk/alpine", p.Name)
-		if err == nil && product != nil {
-			result.MatchedProduct = product.Name
-			result = s.evaluateEOLStatus(result, cycles, p.Version)
-			return result
+		attempts = append(attempts, attempt)
+	}
+	s.trace.add(p, attempts)
+
+	// Unmatched components get the products they resemble, for curating mappings
+	if !matched && s.config.SuggestProducts {
+		result.Suggestions = s.suggestProducts(p)
+	}
+	return result
+}
+
+// newComponentResult creates the unmatched result for a package
+func newComponentResult(p pkg.Package) ComponentResult {
+	return ComponentResult{
+		Name:          p.Name,
+		Version:       p.Version,
+		PURL:          p.PURL,
+		Type:          string(p.Type),
+		Status:        StatusUnknown,
+		SourcePackage: packageSource(p),
+		Vendor:        packageVendor(p),
+		Locations:     packageLocations(p),
+	}
+}
+
+// applyMatch records the matched product and its provenance, then evaluates the EOL status
+func (s *Scanner) applyMatch(result ComponentResult, p pkg.Package, step matchStep, candidate *MatchCandidate) ComponentResult {
+	result.MatchedProduct = candidate.Product.Name
+	result.Match = newMatchInfo(step.Method, candidate.Product.Match, p)
+	if candidate.Confidence != "" {
+		// Matchers may grade their own candidates
+		result.Match.Confidence = candidate.Confidence
+	}
+
+	version := p.Version
+	switch {
+	case candidate.Version != "":
+		version = candidate.Version
+	case step.Version != "":
+		version = step.Version
+	}
+	return s.evaluateEOLStatus(result, candidate.Cycles, version)
+}
+
+// newMatchInfo derives the provenance of a match from the lookup step that hit and
+// the identifier the database matched on
+func newMatchInfo(method MatchMethod, match *db.LookupMatch, p pkg.Package) *MatchInfo {
+	info := &MatchInfo{Method: method}
+	if match == nil {
+		return info
+	}
+	info.Identifier = match.Identifier
+	for _, alternative := range match.Alternatives {
+		a := MatchAlternative{Product: alternative.Name}
+		if alternative.Match != nil {
+			a.Identifier = alternative.Match.Identifier
 		}
+		info.Alternatives = append(info.Alternatives, a)
 	}
+	info.Ambiguous = match.Ambiguous
 
-	// Try PURL type lookup for language packages (pypi, npm, gem, etc.)
-	purlType := getPURLTypeFromPackageType(string(p.Type))
-	if purlType != "" {
-		product, cycles, err := s.dbManager.LookupByPURLPrefix(purlType, p.Name)
-		if err == nil && product != nil {
-			result.MatchedProduct = product.Name
-			result = s.evaluateEOLStatus(result, cycles, p.Version)
-			return result
+	if method == MatchUserMapping {
+		// The user asserted the product; record the mapped product name
+		info.Confidence = ConfidenceHigh
+		return info
+	}
+
+	switch {
+	case method == MatchExactPURL && match.Method == db.MatchMethodPURLPrefix:
+		// The PURL only matched on type, namespace and name, not its version
+		info.Method = MatchPURLPrefix
+	case method == MatchName:
+		// Name lookups fall back from the product name to aliases and repology
+		switch match.Method {
+		case db.MatchMethodAlias:
+			info.Method = MatchAlias
+		case db.MatchMethodRepology:
+			info.Method = MatchRepology
 		}
 	}
 
-	// Try generic PURL lookup
-	product, cycles, err := s.dbManager.LookupByPURLPrefix("generic", p.Name)
-	if err == nil && product != nil {
-		result.MatchedProduct = product.Name
-		result = s.evaluateEOLStatus(result, cycles, p.Version)
-		return result
+	info.Confidence = matchConfidence(info.Method, match, p)
+	if info.Ambiguous && info.Confidence == ConfidenceHigh {
+		// Ties between equally ranked products are broken by name
+		info.Confidence = ConfidenceMedium
 	}
+	return info
+}
 
-	// Try CPE matching if package has CPEs
-	if len(p.CPEs) > 0 {
-		for _, c := range p.CPEs {
-			cpeStr := c.Attributes.String()
-			product, cycles, err := s.dbManager.LookupByCPE(cpeStr)
-			if err == nil && product != nil {
-				result.MatchedProduct = product.Name
-				result = s.evaluateEOLStatus(result, cycles, p.Version)
-				return result
-			}
+// matchConfidence grades a match. Prefix matches are only trusted when the identifier
+// names the package itself (pkg:npm/express must not match express-session), and name
+// based matches are weak for language packages since product names are not scoped to
+// an ecosystem.
+func matchConfidence(method MatchMethod, match *db.LookupMatch, p pkg.Package) MatchConfidence {
+	switch method {
+	case MatchExactPURL, MatchRuntime, MatchMaven, MatchVersionedName:
+		return ConfidenceHigh
+	case MatchPURLPrefix, MatchDistroPURL:
+		if strings.EqualFold(purlName(match.Identifier), p.Name) {
+			return ConfidenceHigh
 		}
+		return ConfidenceLow
+	case MatchCPE:
+		if match.Method == db.MatchMethodCPE {
+			return ConfidenceHigh
+		}
+		return ConfidenceMedium
+	case MatchSourcePackage:
+		// Trusted when the identifier names the source package itself
+		if strings.EqualFold(purlName(match.Identifier), sourcePackageName(p)) {
+			return ConfidenceHigh
+		}
+		return ConfidenceMedium
+	default:
+		if isLanguagePackage(string(p.Type)) {
+			return ConfidenceLow
+		}
+		return ConfidenceMedium
 	}
+}
 
-	// Fallback: try to look up by package name (for products like nginx, postgresql, etc.)
-	product, cycles, err = s.dbManager.LookupByName(p.Name, string(p.Type))
-	if err == nil && product != nil {
-		result.MatchedProduct = product.Name
-		result = s.evaluateEOLStatus(result, cycles, p.Version)
+// purlName extracts the package name from a PURL (pkg:type/namespace/name@version?qualifiers)
+func purlName(purl string) string {
+	if idx := strings.IndexAny(purl, "?#"); idx >= 0 {
+		purl = purl[:idx]
+	}
+	if idx := strings.LastIndex(purl, "@"); idx >= 0 && idx > strings.LastIndex(purl, "/") {
+		purl = purl[:idx]
 	}
+	if idx := strings.LastIndex(purl, "/"); idx >= 0 {
+		purl = purl[idx+1:]
+	}
+	return purl
+}
 
-	return result
+// isLanguagePackage reports whether a Syft package type belongs to a language ecosystem
+func isLanguagePackage(pkgType string) bool {
+	switch pkgType {
+	case "deb", "rpm", "apk", "binary", "os", "":
+		return false
+	default:
+		return true
+	}
 }
 
 // checkOSEOL checks the operating system EOL status
@@ -465,19 +805,34 @@ func (s *Scanner) checkOSEOL(distro *linux.Release) *OSInfo {
 		Status:     StatusUnknown,
 	}
 
-	// Map distro ID to product name in EOL database
-	productName := mapDistroToProduct(distro.ID)
+	// Map distro ID to product name in EOL database, user mappings first
+	productName := s.distroProduct(distro.ID)
 	if productName == "" {
 		return osInfo
 	}
 
+	// Rolling and testing releases (Debian sid, Arch, Tumbleweed) have no cycle to
+	// evaluate, whether or not the database knows the distro
+	rolling := isRollingRelease(distro)
+	if rolling {
+		osInfo.Status = StatusRolling
+	}
+
 	// Look up the OS in the database
-	product, cycles, err := s.dbManager.LookupByName(productName, "os")
+	product, cycles, err := s.lookups().LookupByName(productName, "os")
 	if err != nil || product == nil {
 		return osInfo
 	}
 
 	osInfo.MatchedProduct = product.Name
+	osInfo.Match = &MatchInfo{
+		Method:     MatchOSRelease,
+		Identifier: "ID=" + distro.ID,
+		Confidence: ConfidenceHigh,
+	}
+	if rolling {
+		return osInfo
+	}
 
 	// Find matching cycle based on version
 	versionToMatch := distro.VersionID
@@ -485,7 +840,8 @@ func (s *Scanner) checkOSEOL(distro *linux.Release) *OSInfo {
 		versionToMatch = distro.Version
 	}
 
-	// Evaluate EOL status using the same logic as components
+	// Evaluate EOL status using the same logic as components. Point releases
+	// (alpine 3.18.4, debian 12.4) match their cycle (3.18, 12) by prefix.
 	result := ComponentResult{
 		Name:    osInfo.Name,
 		Version: versionToMatch,
@@ -493,45 +849,79 @@ func (s *Scanner) checkOSEOL(distro *linux.Release) *OSInfo {
 	}
 	result = s.evaluateEOLStatus(result, cycles, versionToMatch)
 
+	// Releases without a usable version are resolved by codename (bookworm, jammy)
+	if result.MatchedCycle == "" {
+		codename := osCodename(distro)
+		if cycle := findCycleByCodename(cycles, codename); cycle != nil {
+			result = s.evaluateEOLStatus(ComponentResult{Name: osInfo.Name, Version: cycle.Cycle, Status: StatusUnknown}, cycles, cycle.Cycle)
+			osInfo.Match.Identifier = "VERSION_CODENAME=" + codename
+		}
+	}
+
 	osInfo.Status = result.Status
 	osInfo.EOLDate = result.EOLDate
 	osInfo.DaysUntilEOL = result.DaysUntilEOL
+	osInfo.SupportEndDate = result.SupportEndDate
+	osInfo.DaysUntilSupportEnd = result.DaysUntilSupportEnd
+	osInfo.ExtendedSupportEndDate = result.ExtendedSupportEndDate
+	osInfo.DaysUntilExtendedSupportEnd = result.DaysUntilExtendedSupportEnd
+	osInfo.HasExtendedSupport = result.HasExtendedSupport
+	osInfo.ExtendedSupportApplied = result.ExtendedSupportApplied
+	osInfo.DiscontinuedDate = result.DiscontinuedDate
+	osInfo.IsDiscontinued = result.IsDiscontinued
 	osInfo.MatchedCycle = result.MatchedCycle
+	osInfo.AmbiguousCycles = result.AmbiguousCycles
+	osInfo.CycleFallback = result.CycleFallback
+	if result.CycleFallback && osInfo.Match != nil {
+		osInfo.Match.Confidence = ConfidenceLow
+	}
 	osInfo.IsLTS = result.IsLTS
+	osInfo.Recommendations = result.Recommendations
 
 	return osInfo
 }
 
+// distroProduct returns the product of a distro ID, from the user mappings first
+func (s *Scanner) distroProduct(distroID string) string {
+	if product, ok := s.mappings.distroProduct(distroID); ok {
+		return product
+	}
+	return mapDistroToProduct(distroID)
+}
+
 // mapDistroToProduct maps Linux distribution IDs to endoflife.date product names
 func mapDistroToProduct(distroID string) string {
 	// Map common distro IDs to their product names in endoflife.date
 	distroMap := map[string]string{
-		"debian":       "debian",
-		"ubuntu":       "ubuntu",
-		"alpine":       "alpine-linux",
-		"centos":       "centos",
-		"rhel":         "rhel",
-		"fedora":       "fedora",
-		"amzn":         "amazon-linux",
-		"amazonlinux":  "amazon-linux",
-		"almalinux":    "almalinux",
-		"rocky":        "rocky-linux",
-		"opensuse":     "opensuse",
-		"sles":         "sles",
-		"ol":           "oracle-linux",
-		"oraclelinux":  "oracle-linux",
-		"arch":         "arch",
-		"manjaro":      "manjaro",
-		"linuxmint":    "linuxmint",
-		"pop":          "pop-os",
-		"elementary":   "elementary-os",
-		"nixos":        "nixos",
-		"void":         "void-linux",
-		"gentoo":       "gentoo",
-		"slackware":    "slackware",
-		"photon":       "photon",
-		"clear-linux":  "clear-linux",
-		"flatcar":      "flatcar",
+		"debian":      "debian",
+		"ubuntu":      "ubuntu",
+		"alpine":      "alpine-linux",
+		"centos":      "centos",
+		"rhel":        "rhel",
+		"fedora":      "fedora",
+		"amzn":        "amazon-linux",
+		"amazonlinux": "amazon-linux",
+		"almalinux":   "almalinux",
+		"rocky":       "rocky-linux",
+		"opensuse":    "opensuse",
+		"sles":        "sles",
+		"ol":          "oracle-linux",
+		"oraclelinux": "oracle-linux",
+		"arch":        "arch",
+		"manjaro":     "manjaro",
+		"linuxmint":   "linuxmint",
+		"pop":         "pop-os",
+		"elementary":  "elementary-os",
+		"nixos":       "nixos",
+		"void":        "void-linux",
+		"gentoo":      "gentoo",
+		"slackware":   "slackware",
+		"photon":      "photon",
+		"clear-linux": "clear-linux",
+		"flatcar":     "flatcar",
+
+		// openSUSE Leap reports its own ID
+		"opensuse-leap": "opensuse",
 	}
 
 	if product, ok := distroMap[strings.ToLower(distroID)]; ok {
@@ -573,6 +963,7 @@ func getPURLTypeFromPackageType(pkgType string) string {
 		"java-archive":   "maven",
 		"jenkins-plugin": "maven",
 		"nuget":          "nuget",
+		"dotnet":         "nuget",
 		"composer":       "composer",
 		"conan":          "conan",
 		"apk":            "apk",
@@ -588,113 +979,461 @@ func (s *Scanner) evaluateEOLStatus(result ComponentResult, cycles []db.Cycle, v
 		return result
 	}
 
-	today := time.Now()
-	forwardDate := today.AddDate(0, 0, s.config.ForwardLookupDays)
+	// Parse the version according to its ecosystem so epochs, distro revisions
+	// and qualifiers do not get in the way of cycle matching
+	parsed := ParseVersion(version, versionSchemeForType(result.Type))
 
-	// Try to find a matching cycle based on version
-	var matchedCycle *db.Cycle
+	cycles = sortCyclesNewestFirst(cycles)
+	matchedIndex, ambiguous, fallback := selectCycle(parsed, cycles)
+	if matchedIndex < 0 {
+		return result
+	}
+	matchedCycle := &cycles[matchedIndex]
+	result.AmbiguousCycles = ambiguous
+	result.CycleFallback = fallback
+	if fallback && result.Match != nil {
+		// The product may be right, but the cycle is a guess
+		result.Match.Confidence = ConfidenceLow
+	}
+
+	result.MatchedCycle = matchedCycle.Cycle
+	result.IsLTS = matchedCycle.LTS == 1
+
+	if matchedCycle.LatestVersion.Valid {
+		result.LatestVersion = matchedCycle.LatestVersion.String
+	}
+
+	// A bare cycle (e.g. taken from a package name) says nothing about the patch level
+	if version != matchedCycle.Cycle {
+		result = evaluatePatchLevel(result, matchedCycle, parsed, time.Now())
+	}
+	result = s.evaluateCycle(result, matchedCycle)
+
+	if result.Status == StatusEOL || result.Status == StatusEOLSoon {
+		result.Recommendations = s.recommendUpgrades(cycles, matchedIndex)
+	}
+
+	return result
+}
+
+// sortCyclesNewestFirst returns a copy of the cycles ordered by release date, newest
+// first. Cycles without a release date go last; ties are broken by the cycle version
+// so that the order does not depend on how the database returned them.
+func sortCyclesNewestFirst(cycles []db.Cycle) []db.Cycle {
+	sorted := make([]db.Cycle, len(cycles))
+	copy(sorted, cycles)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		aDate, aOK := parseCycleDate(a.ReleaseDate)
+		bDate, bOK := parseCycleDate(b.ReleaseDate)
+		if aOK != bOK {
+			return aOK
+		}
+		if aOK && !aDate.Equal(bDate) {
+			return aDate.After(bDate)
+		}
+		return compareCycleNames(a.Cycle, b.Cycle) > 0
+	})
+
+	return sorted
+}
+
+// selectCycle picks the cycle a version belongs to. The most specific matching cycle
+// wins (e.g. "1.2" over "1" for 1.2.3). When nothing matches exactly, cycles with the
+// same major version are considered and the closest one at or below the version is
+// chosen; a version older than all of them stays unmatched, since it cannot have
+// better support than the oldest cycle on record. The names of other cycles that matched equally well are returned so the
+// ambiguity can be reported, and a fallback cycle is flagged as such since no cycle
+// matched.
+func selectCycle(version Version, cycles []db.Cycle) (int, []string, bool) {
+	var candidates []int
+	bestSpecificity := 0
 	for i, cycle := range cycles {
-		// Check if the version matches or starts with the cycle name
-		if matchesVersion(version, cycle.Cycle) {
-			matchedCycle = &cycles[i]
-			break
+		if !version.MatchesCycle(cycle.Cycle) {
+			continue
+		}
+		specificity := cycleSpecificity(cycle.Cycle)
+		switch {
+		case specificity > bestSpecificity:
+			bestSpecificity = specificity
+			candidates = []int{i}
+		case specificity == bestSpecificity:
+			candidates = append(candidates, i)
 		}
 	}
 
-	// If no specific match, use the first cycle as a reference
-	if matchedCycle == nil && len(cycles) > 0 {
-		// Try to match major version
-		for i, cycle := range cycles {
-			if matchesMajorVersion(version, cycle.Cycle) {
-				matchedCycle = &cycles[i]
-				break
+	if len(candidates) > 0 {
+		best := candidates[0]
+		for _, i := range candidates[1:] {
+			if preferCycle(version, cycles[i], cycles[best]) {
+				best = i
 			}
 		}
+		return best, otherCycles(cycles, candidates, best), false
+	}
+
+	// Fall back to cycles sharing the major version
+	for i, cycle := range cycles {
+		if version.MatchesMajor(cycle.Cycle) {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return -1, nil, false
+	}
+
+	best := -1
+	for _, i := range candidates {
+		cycleVersion := ParseVersion(cycles[i].Cycle, SchemeGeneric)
+		if _, cmp := compareVersionSegments(cycleVersion.Release, version.Release); cmp > 0 {
+			continue
+		}
+		if best < 0 || compareCycleNames(cycles[i].Cycle, cycles[best].Cycle) > 0 {
+			best = i
+		}
+	}
+	if best < 0 {
+		// The version predates every cycle of its major
+		return -1, nil, false
+	}
+
+	return best, nil, true
+}
+
+// cycleSpecificity returns how many version components a cycle name pins down
+func cycleSpecificity(cycle string) int {
+	parsed := ParseVersion(cycle, SchemeGeneric)
+	if len(parsed.Release) > 0 && parsed.Qualifier == "" {
+		return len(parsed.Release)
+	}
+	return len(strings.FieldsFunc(cycle, func(r rune) bool { return r == '.' || r == '-' }))
+}
+
+// preferCycle breaks ties between two equally specific cycles: a cycle whose name is
+// literally a prefix of the version wins, then the newest release, then the higher name
+func preferCycle(version Version, a, b db.Cycle) bool {
+	aPrefix := strings.HasPrefix(version.Upstream, a.Cycle)
+	bPrefix := strings.HasPrefix(version.Upstream, b.Cycle)
+	if aPrefix != bPrefix {
+		return aPrefix
 	}
 
-	if matchedCycle == nil {
+	aDate, aOK := parseCycleDate(a.ReleaseDate)
+	bDate, bOK := parseCycleDate(b.ReleaseDate)
+	if aOK != bOK {
+		return aOK
+	}
+	if aOK && !aDate.Equal(bDate) {
+		return aDate.After(bDate)
+	}
+
+	return compareCycleNames(a.Cycle, b.Cycle) > 0
+}
+
+// compareCycleNames orders cycle names numerically where possible (e.g. "10" > "9")
+func compareCycleNames(a, b string) int {
+	aVersion := ParseVersion(a, SchemeGeneric)
+	bVersion := ParseVersion(b, SchemeGeneric)
+	if _, cmp := compareVersionSegments(aVersion.Release, bVersion.Release); cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(a, b)
+}
+
+// otherCycles returns the sorted names of the candidate cycles other than the selected one
+func otherCycles(cycles []db.Cycle, candidates []int, selected int) []string {
+	var names []string
+	for _, i := range candidates {
+		if i != selected {
+			names = append(names, cycles[i].Cycle)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return compareCycleNames(names[i], names[j]) > 0
+	})
+	return names
+}
+
+// recommendUpgrades picks an upgrade target for an EOL or EOL-soon component from the
+// product cycles (ordered newest first). Support ends with the cycle, so only a newer
+// cycle buys more of it: products that publish LTS cycles get the newest maintained
+// LTS, others the nearest maintained newer cycle.
+func (s *Scanner) recommendUpgrades(cycles []db.Cycle, matchedIndex int) []Recommendation {
+	today := time.Now()
+	forwardDate := today.AddDate(0, 0, s.config.ForwardLookupDays)
+
+	var recommendations []Recommendation
+	var nearest, newestLTS *db.Cycle
+	for i := matchedIndex - 1; i >= 0; i-- {
+		cycle := &cycles[i]
+		if !isCycleMaintained(cycle, forwardDate) {
+			continue
+		}
+		if nearest == nil {
+			nearest = cycle
+		}
+		if cycle.LTS == 1 {
+			newestLTS = cycle
+		}
+	}
+
+	switch {
+	case newestLTS != nil:
+		recommendations = append(recommendations, newRecommendation(StrategyNewestLTS, newestLTS, today))
+	case nearest != nil:
+		recommendations = append(recommendations, newRecommendation(StrategyNearestMaintained, nearest, today))
+	}
+
+	return recommendations
+}
+
+// isCycleMaintained reports whether a cycle is still supported beyond the given date
+func isCycleMaintained(cycle *db.Cycle, until time.Time) bool {
+	if cycle.EOLBoolean.Valid && cycle.EOLBoolean.Int64 == 1 {
+		return false
+	}
+	if eolDate, ok := parseCycleDate(cycle.EOL); ok {
+		return eolDate.After(until)
+	}
+	return cycle.IsMaintained == 1 || (cycle.EOLBoolean.Valid && cycle.EOLBoolean.Int64 == 0)
+}
+
+// newRecommendation builds a recommendation targeting the given cycle
+func newRecommendation(strategy RecommendationStrategy, cycle *db.Cycle, today time.Time) Recommendation {
+	recommendation := Recommendation{
+		Strategy: strategy,
+		Cycle:    cycle.Cycle,
+		IsLTS:    cycle.LTS == 1,
+	}
+	if cycle.LatestVersion.Valid {
+		recommendation.Version = cycle.LatestVersion.String
+	}
+	if eolDate, ok := parseCycleDate(cycle.EOL); ok {
+		recommendation.EOLDate = cycle.EOL.String
+		if eolDate.After(today) {
+			days := int(eolDate.Sub(today).Hours() / 24)
+			recommendation.DaysUntilEOL = &days
+		}
+	}
+	return recommendation
+}
+
+// evaluatePatchLevel compares the installed version with the latest release of its
+// cycle. A component can sit in a supported cycle and still miss months of patches.
+func evaluatePatchLevel(result ComponentResult, cycle *db.Cycle, installed Version, today time.Time) ComponentResult {
+	if !cycle.LatestVersion.Valid || cycle.LatestVersion.String == "" {
 		return result
 	}
 
-	result.MatchedCycle = matchedCycle.Cycle
-	result.IsLTS = matchedCycle.LTS == 1
+	latestReleaseDate, hasLatestReleaseDate := parseCycleDate(cycle.LatestReleaseDate)
+	if hasLatestReleaseDate {
+		result.LatestReleaseDate = cycle.LatestReleaseDate.String
+	}
 
-	if matchedCycle.LatestVersion.Valid {
-		result.LatestVersion = matchedCycle.LatestVersion.String
+	latest := ParseVersion(cycle.LatestVersion.String, installed.Scheme)
+	if len(installed.Release) == 0 || len(latest.Release) == 0 {
+		return result
 	}
 
+	// The latest version is a plain upstream release, so the epoch and distro
+	// revision of the installed package must not take part in the comparison
+	if installed.CompareRelease(latest) >= 0 {
+		return result
+	}
+
+	result.OutdatedInCycle = true
+
+	// Releases behind is counted on the first component that differs,
+	// e.g. 20.1.0 is 17 minor releases behind 20.18.0
+	if index, cmp := compareVersionSegments(installed.Release, latest.Release); cmp < 0 {
+		releasesBehind := segmentAt(latest.Release, index) - segmentAt(installed.Release, index)
+		result.ReleasesBehind = &releasesBehind
+	}
+
+	// The cycle records only its latest release, so how long the installed version has
+	// been behind is unknown; report how long the fix has been available instead
+	if hasLatestReleaseDate && latestReleaseDate.Before(today) {
+		daysSinceLatestRelease := int(today.Sub(latestReleaseDate).Hours() / 24)
+		result.DaysSinceLatestRelease = &daysSinceLatestRelease
+	}
+
+	return result
+}
+
+// evaluateCycle determines the lifecycle status of a component within its matched cycle
+func (s *Scanner) evaluateCycle(result ComponentResult, cycle *db.Cycle) ComponentResult {
+	today := time.Now()
+	forwardDate := today.AddDate(0, 0, s.config.ForwardLookupDays)
+
+	result = evaluateExtendedSupport(result, cycle, today)
+
 	// Check EOL status
-	if matchedCycle.EOLBoolean.Valid && matchedCycle.EOLBoolean.Int64 == 1 {
+	eolDate, hasEOLDate := parseCycleDate(cycle.EOL)
+	switch {
+	case cycle.EOLBoolean.Valid && cycle.EOLBoolean.Int64 == 1:
 		// Boolean EOL - already EOL
 		result.Status = StatusEOL
+	case hasEOLDate:
+		result.EOLDate = cycle.EOL.String
+		result.Status, result.DaysUntilEOL = dateStatus(eolDate, today, forwardDate)
+	case cycle.IsMaintained == 1:
+		// If we have a matched cycle but no EOL info, mark as active
+		result.Status = StatusActive
+	}
+
+	result = evaluateSupportStatus(result, cycle, today)
+
+	if s.config.ExtendedSupport {
+		result = applyExtendedSupport(result, cycle, today, forwardDate)
+	}
+
+	return result
+}
+
+// dateStatus classifies a lifecycle end date relative to today and the forward lookup window.
+// The days count is nil once the date has passed.
+func dateStatus(date, today, forwardDate time.Time) (EOLStatus, *int) {
+	if date.Before(today) || date.Equal(today) {
+		return StatusEOL, nil
+	}
+
+	days := int(date.Sub(today).Hours() / 24)
+	if date.Before(forwardDate) {
+		return StatusEOLSoon, &days
+	}
+	return StatusActive, &days
+}
+
+// parseCycleDate parses a nullable cycle date column
+func parseCycleDate(value sql.NullString) (time.Time, bool) {
+	if !value.Valid || value.String == "" {
+		return time.Time{}, false
+	}
+	date, err := parseEOLDate(value.String)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// evaluateSupportStatus fills in the end of active support for a cycle that is
+// not yet EOL, downgrading active components to security-only once it has passed
+func evaluateSupportStatus(result ComponentResult, cycle *db.Cycle, today time.Time) ComponentResult {
+	if result.Status == StatusEOL {
 		return result
 	}
 
-	if matchedCycle.EOL.Valid && matchedCycle.EOL.String != "" {
-		eolDate, err := parseEOLDate(matchedCycle.EOL.String)
-		if err == nil {
-			result.EOLDate = matchedCycle.EOL.String
+	if supportDate, ok := parseCycleDate(cycle.Support); ok {
+		result.SupportEndDate = cycle.Support.String
 
-			if eolDate.Before(today) || eolDate.Equal(today) {
-				result.Status = StatusEOL
-			} else if eolDate.Before(forwardDate) {
-				result.Status = StatusEOLSoon
-				days := int(eolDate.Sub(today).Hours() / 24)
-				result.DaysUntilEOL = &days
-			} else {
-				result.Status = StatusActive
-				days := int(eolDate.Sub(today).Hours() / 24)
-				result.DaysUntilEOL = &days
+		if supportDate.Before(today) || supportDate.Equal(today) {
+			if result.Status == StatusActive {
+				result.Status = StatusSecurityOnly
 			}
-			return result
+		} else {
+			days := int(supportDate.Sub(today).Hours() / 24)
+			result.DaysUntilSupportEnd = &days
 		}
+		return result
 	}
 
-	// If we have a matched cycle but no EOL info, mark as active
-	if matchedCycle.IsMaintained == 1 {
-		result.Status = StatusActive
+	// Boolean support flag - active support already ended
+	if cycle.SupportBoolean.Valid && cycle.SupportBoolean.Int64 == 1 && result.Status == StatusActive {
+		result.Status = StatusSecurityOnly
 	}
 
 	return result
 }
 
-// matchesVersion checks if a version matches a cycle
-func matchesVersion(version, cycle string) bool {
-	// Exact match
-	if version == cycle {
-		return true
+// evaluateExtendedSupport fills in the extended (commercial) support and discontinued
+// information of a cycle without changing the component status
+func evaluateExtendedSupport(result ComponentResult, cycle *db.Cycle, today time.Time) ComponentResult {
+	if extendedDate, ok := parseCycleDate(cycle.ExtendedSupport); ok {
+		result.ExtendedSupportEndDate = cycle.ExtendedSupport.String
+		if extendedDate.After(today) {
+			result.HasExtendedSupport = true
+			days := int(extendedDate.Sub(today).Hours() / 24)
+			result.DaysUntilExtendedSupportEnd = &days
+		}
+	} else if cycle.ExtendedSupportBoolean.Valid && cycle.ExtendedSupportBoolean.Int64 == 0 {
+		// Extended support is offered but no end date has been announced
+		result.HasExtendedSupport = true
 	}
 
-	// Version starts with cycle (e.g., "3.9.1" matches cycle "3.9")
-	if strings.HasPrefix(version, cycle+".") || strings.HasPrefix(version, cycle+"-") {
-		return true
+	if discontinuedDate, ok := parseCycleDate(cycle.Discontinued); ok {
+		result.DiscontinuedDate = cycle.Discontinued.String
+		result.IsDiscontinued = !discontinuedDate.After(today)
+	} else if cycle.DiscontinuedBoolean.Valid && cycle.DiscontinuedBoolean.Int64 == 1 {
+		result.IsDiscontinued = true
 	}
 
-	return false
+	return result
 }
 
-// matchesMajorVersion checks if version's major component matches cycle
-func matchesMajorVersion(version, cycle string) bool {
-	// Extract major version from both
-	vMajor := extractMajorVersion(version)
-	cMajor := extractMajorVersion(cycle)
+// applyExtendedSupport re-evaluates an EOL or EOL-soon component against the end of
+// extended support. Cycles covered by extended support only receive security fixes,
+// so they are reported as security-only rather than active.
+func applyExtendedSupport(result ComponentResult, cycle *db.Cycle, today, forwardDate time.Time) ComponentResult {
+	if result.Status != StatusEOL && result.Status != StatusEOLSoon {
+		return result
+	}
+
+	if extendedDate, ok := parseCycleDate(cycle.ExtendedSupport); ok {
+		status, _ := dateStatus(extendedDate, today, forwardDate)
+		if status == StatusEOL {
+			return result
+		}
+		if status == StatusActive {
+			status = StatusSecurityOnly
+		}
+		result.Status = status
+		result.ExtendedSupportApplied = true
+		return result
+	}
+
+	if result.HasExtendedSupport {
+		result.Status = StatusSecurityOnly
+		result.ExtendedSupportApplied = true
+	}
 
-	return vMajor != "" && vMajor == cMajor
+	return result
 }
 
-// extractMajorVersion extracts the major version component
-func extractMajorVersion(version string) string {
-	// Remove leading 'v' if present
-	v := strings.TrimPrefix(version, "v")
+// addComponent appends a component result and updates the status counts
+func (summary *ScanSummary) addComponent(result ComponentResult) {
+	summary.Components = append(summary.Components, result)
+	summary.countComponent(result)
+}
 
-	// Split by common delimiters
-	for _, sep := range []string{".", "-", "_"} {
-		parts := strings.Split(v, sep)
-		if len(parts) > 0 {
-			return parts[0]
-		}
+// countComponent updates the status counts for a component
+func (summary *ScanSummary) countComponent(result ComponentResult) {
+	summary.TotalComponents++
+
+	switch result.Status {
+	case StatusEOL:
+		summary.EOLComponents++
+	case StatusEOLSoon:
+		summary.EOLSoonComponents++
+	case StatusSecurityOnly:
+		summary.SecurityOnlyComponents++
+	case StatusActive:
+		summary.ActiveComponents++
+	case StatusRolling:
+		summary.RollingComponents++
+	case StatusUnknown:
+		summary.UnknownComponents++
+	}
+
+	if result.OutdatedInCycle {
+		summary.OutdatedInCycleComponents++
+	}
+	if result.OriginRelease != nil {
+		summary.MixedReleaseComponents++
+	}
+	if result.DistroSupported() {
+		summary.DistroSupportedComponents++
 	}
-	return v
 }
 
 // GetEOLComponents returns only the components that are EOL or EOL soon
@@ -2,202 +2,18 @@ package scanning
 
 import (
 	"database/sql"
+	"fmt"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/anchore/syft/syft/cpe"
+	"github.com/anchore/syft/syft/linux"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
 	"github.com/j0356/eol-scanner/core/db"
 )
 
-// TestMatchesVersion tests the matchesVersion function
-func TestMatchesVersion(t *testing.T) {
-	tests := []struct {
-		name    string
-		version string
-		cycle   string
-		want    bool
-	}{
-		{
-			name:    "exact match",
-			version: "3.9",
-			cycle:   "3.9",
-			want:    true,
-		},
-		{
-			name:    "version with patch matches cycle",
-			version: "3.9.1",
-			cycle:   "3.9",
-			want:    true,
-		},
-		{
-			name:    "version with dash suffix matches cycle",
-			version: "3.9-alpine",
-			cycle:   "3.9",
-			want:    true,
-		},
-		{
-			name:    "version with multiple patches matches cycle",
-			version: "3.9.10.2",
-			cycle:   "3.9",
-			want:    true,
-		},
-		{
-			name:    "version does not match different cycle",
-			version: "3.10.1",
-			cycle:   "3.9",
-			want:    false,
-		},
-		{
-			name:    "partial match should fail",
-			version: "3.91",
-			cycle:   "3.9",
-			want:    false,
-		},
-		{
-			name:    "major version only",
-			version: "22",
-			cycle:   "22",
-			want:    true,
-		},
-		{
-			name:    "version with build metadata",
-			version: "1.21.0",
-			cycle:   "1.21",
-			want:    true,
-		},
-		{
-			name:    "empty version",
-			version: "",
-			cycle:   "3.9",
-			want:    false,
-		},
-		{
-			name:    "empty cycle",
-			version: "3.9.1",
-			cycle:   "",
-			want:    false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := matchesVersion(tt.version, tt.cycle)
-			if got != tt.want {
-				t.Errorf("matchesVersion(%q, %q) = %v, want %v", tt.version, tt.cycle, got, tt.want)
-			}
-		})
-	}
-}
-
-// TestMatchesMajorVersion tests the matchesMajorVersion function
-func TestMatchesMajorVersion(t *testing.T) {
-	tests := []struct {
-		name    string
-		version string
-		cycle   string
-		want    bool
-	}{
-		{
-			name:    "same major version",
-			version: "3.9.1",
-			cycle:   "3.8",
-			want:    true,
-		},
-		{
-			name:    "different major versions",
-			version: "4.0.0",
-			cycle:   "3.9",
-			want:    false,
-		},
-		{
-			name:    "version with v prefix",
-			version: "v2.1.0",
-			cycle:   "2.0",
-			want:    true,
-		},
-		{
-			name:    "single digit versions",
-			version: "22",
-			cycle:   "22",
-			want:    true,
-		},
-		{
-			name:    "version with dash - no match due to extraction behavior",
-			version: "3-alpine",
-			cycle:   "3",
-			want:    false, // extractMajorVersion returns "3-alpine", not "3"
-		},
-		{
-			name:    "empty version",
-			version: "",
-			cycle:   "3",
-			want:    false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := matchesMajorVersion(tt.version, tt.cycle)
-			if got != tt.want {
-				t.Errorf("matchesMajorVersion(%q, %q) = %v, want %v", tt.version, tt.cycle, got, tt.want)
-			}
-		})
-	}
-}
-
-// TestExtractMajorVersion tests the extractMajorVersion function
-func TestExtractMajorVersion(t *testing.T) {
-	tests := []struct {
-		name    string
-		version string
-		want    string
-	}{
-		{
-			name:    "semver format",
-			version: "3.9.1",
-			want:    "3",
-		},
-		{
-			name:    "with v prefix",
-			version: "v2.1.0",
-			want:    "2",
-		},
-		{
-			name:    "single number",
-			version: "22",
-			want:    "22",
-		},
-		{
-			name:    "dash separator - splits on dot first",
-			version: "3-alpine",
-			want:    "3-alpine", // Implementation splits on "." first, which doesn't exist
-		},
-		{
-			name:    "underscore separator - splits on dot first",
-			version: "3_0_1",
-			want:    "3_0_1", // Implementation splits on "." first, which doesn't exist
-		},
-		{
-			name:    "empty string",
-			version: "",
-			want:    "",
-		},
-		{
-			name:    "complex version",
-			version: "2024.01.15",
-			want:    "2024",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := extractMajorVersion(tt.version)
-			if got != tt.want {
-				t.Errorf("extractMajorVersion(%q) = %q, want %q", tt.version, got, tt.want)
-			}
-		})
-	}
-}
-
 // TestMapDistroToProduct tests the mapDistroToProduct function
 func TestMapDistroToProduct(t *testing.T) {
 	tests := []struct {
@@ -555,9 +371,15 @@ func TestEOLStatusConstants(t *testing.T) {
 	if StatusEOLSoon != "eol_soon" {
 		t.Errorf("StatusEOLSoon = %q, want %q", StatusEOLSoon, "eol_soon")
 	}
+	if StatusSecurityOnly != "security_only" {
+		t.Errorf("StatusSecurityOnly = %q, want %q", StatusSecurityOnly, "security_only")
+	}
 	if StatusUnknown != "unknown" {
 		t.Errorf("StatusUnknown = %q, want %q", StatusUnknown, "unknown")
 	}
+	if StatusRolling != "rolling" {
+		t.Errorf("StatusRolling = %q, want %q", StatusRolling, "rolling")
+	}
 }
 
 // TestEvaluateEOLStatusWithBooleanEOL tests evaluateEOLStatus when EOL is boolean true
@@ -743,6 +565,938 @@ func TestEvaluateEOLStatusWithLTS(t *testing.T) {
 	}
 }
 
+// TestEvaluateEOLStatusSecurityOnly tests a cycle past active support but before EOL
+func TestEvaluateEOLStatusSecurityOnly(t *testing.T) {
+	scanner := &Scanner{
+		config: &ScannerConfig{
+			ForwardLookupDays: 90,
+		},
+	}
+
+	pastDate := time.Now().AddDate(0, -6, 0).Format("2006-01-02")
+	futureDate := time.Now().AddDate(1, 0, 0).Format("2006-01-02")
+	cycles := []db.Cycle{
+		{
+			Cycle:   "3.11",
+			EOL:     toNullString(futureDate),
+			Support: toNullString(pastDate),
+		},
+	}
+
+	result := ComponentResult{
+		Name:    "python",
+		Version: "3.11.4",
+		Status:  StatusUnknown,
+	}
+
+	result = scanner.evaluateEOLStatus(result, cycles, "3.11.4")
+
+	if result.Status != StatusSecurityOnly {
+		t.Errorf("evaluateEOLStatus() status = %s, want %s", result.Status, StatusSecurityOnly)
+	}
+	if result.SupportEndDate != pastDate {
+		t.Errorf("evaluateEOLStatus() SupportEndDate = %s, want %s", result.SupportEndDate, pastDate)
+	}
+	if result.DaysUntilSupportEnd != nil {
+		t.Errorf("evaluateEOLStatus() DaysUntilSupportEnd = %d, want nil", *result.DaysUntilSupportEnd)
+	}
+	if result.DaysUntilEOL == nil {
+		t.Error("evaluateEOLStatus() DaysUntilEOL should not be nil")
+	}
+}
+
+// TestEvaluateEOLStatusSupportStatus tests how the end of active support affects the status
+func TestEvaluateEOLStatusSupportStatus(t *testing.T) {
+	scanner := &Scanner{
+		config: &ScannerConfig{
+			ForwardLookupDays: 90,
+		},
+	}
+
+	pastDate := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+	soonDate := time.Now().AddDate(0, 0, 30).Format("2006-01-02")
+	futureDate := time.Now().AddDate(2, 0, 0).Format("2006-01-02")
+
+	tests := []struct {
+		name              string
+		cycle             db.Cycle
+		want              EOLStatus
+		wantDaysToSupport bool
+	}{
+		{
+			name:              "active support not yet ended",
+			cycle:             db.Cycle{Cycle: "1", EOL: toNullString(futureDate), Support: toNullString(futureDate)},
+			want:              StatusActive,
+			wantDaysToSupport: true,
+		},
+		{
+			name:  "boolean support ended",
+			cycle: db.Cycle{Cycle: "1", EOL: toNullString(futureDate), SupportBoolean: toNullInt64(1)},
+			want:  StatusSecurityOnly,
+		},
+		{
+			name:  "maintained cycle without EOL date past support",
+			cycle: db.Cycle{Cycle: "1", IsMaintained: 1, Support: toNullString(pastDate)},
+			want:  StatusSecurityOnly,
+		},
+		{
+			name:  "EOL soon takes precedence over security only",
+			cycle: db.Cycle{Cycle: "1", EOL: toNullString(soonDate), Support: toNullString(pastDate)},
+			want:  StatusEOLSoon,
+		},
+		{
+			name:  "EOL takes precedence over security only",
+			cycle: db.Cycle{Cycle: "1", EOL: toNullString(pastDate), Support: toNullString(pastDate)},
+			want:  StatusEOL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ComponentResult{Name: "pkg", Version: "1.2.3", Status: StatusUnknown}
+			result = scanner.evaluateEOLStatus(result, []db.Cycle{tt.cycle}, "1.2.3")
+
+			if result.Status != tt.want {
+				t.Errorf("evaluateEOLStatus() status = %s, want %s", result.Status, tt.want)
+			}
+			if tt.wantDaysToSupport && result.DaysUntilSupportEnd == nil {
+				t.Error("evaluateEOLStatus() DaysUntilSupportEnd should not be nil")
+			}
+		})
+	}
+}
+
+// TestEvaluateEOLStatusExtendedSupport tests reporting and applying extended support
+func TestEvaluateEOLStatusExtendedSupport(t *testing.T) {
+	pastDate := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+	soonDate := time.Now().AddDate(0, 0, 30).Format("2006-01-02")
+	futureDate := time.Now().AddDate(3, 0, 0).Format("2006-01-02")
+
+	tests := []struct {
+		name            string
+		extendedSupport bool
+		cycle           db.Cycle
+		want            EOLStatus
+		wantApplied     bool
+	}{
+		{
+			name:  "extended support reported but not applied by default",
+			cycle: db.Cycle{Cycle: "18.04", EOL: toNullString(pastDate), ExtendedSupport: toNullString(futureDate)},
+			want:  StatusEOL,
+		},
+		{
+			name:            "EOL cycle covered by extended support",
+			extendedSupport: true,
+			cycle:           db.Cycle{Cycle: "18.04", EOL: toNullString(pastDate), ExtendedSupport: toNullString(futureDate)},
+			want:            StatusSecurityOnly,
+			wantApplied:     true,
+		},
+		{
+			name:            "extended support ending soon",
+			extendedSupport: true,
+			cycle:           db.Cycle{Cycle: "18.04", EOL: toNullString(pastDate), ExtendedSupport: toNullString(soonDate)},
+			want:            StatusEOLSoon,
+			wantApplied:     true,
+		},
+		{
+			name:            "extended support already ended",
+			extendedSupport: true,
+			cycle:           db.Cycle{Cycle: "18.04", EOL: toNullString(pastDate), ExtendedSupport: toNullString(pastDate)},
+			want:            StatusEOL,
+		},
+		{
+			name:            "extended support without announced end",
+			extendedSupport: true,
+			cycle:           db.Cycle{Cycle: "18.04", EOLBoolean: toNullInt64(1), ExtendedSupportBoolean: toNullInt64(0)},
+			want:            StatusSecurityOnly,
+			wantApplied:     true,
+		},
+		{
+			name:            "no extended support program",
+			extendedSupport: true,
+			cycle:           db.Cycle{Cycle: "18.04", EOL: toNullString(pastDate)},
+			want:            StatusEOL,
+		},
+		{
+			name:            "active cycle unaffected",
+			extendedSupport: true,
+			cycle:           db.Cycle{Cycle: "18.04", EOL: toNullString(futureDate), ExtendedSupport: toNullString(futureDate)},
+			want:            StatusActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := &Scanner{
+				config: &ScannerConfig{
+					ForwardLookupDays: 90,
+					ExtendedSupport:   tt.extendedSupport,
+				},
+			}
+
+			result := ComponentResult{Name: "ubuntu", Version: "18.04", Status: StatusUnknown}
+			result = scanner.evaluateEOLStatus(result, []db.Cycle{tt.cycle}, "18.04")
+
+			if result.Status != tt.want {
+				t.Errorf("evaluateEOLStatus() status = %s, want %s", result.Status, tt.want)
+			}
+			if result.ExtendedSupportApplied != tt.wantApplied {
+				t.Errorf("evaluateEOLStatus() ExtendedSupportApplied = %v, want %v", result.ExtendedSupportApplied, tt.wantApplied)
+			}
+			if tt.cycle.ExtendedSupport.Valid && result.ExtendedSupportEndDate != tt.cycle.ExtendedSupport.String {
+				t.Errorf("evaluateEOLStatus() ExtendedSupportEndDate = %s, want %s", result.ExtendedSupportEndDate, tt.cycle.ExtendedSupport.String)
+			}
+		})
+	}
+}
+
+// TestEvaluateEOLStatusDiscontinued tests that discontinued information is reported
+func TestEvaluateEOLStatusDiscontinued(t *testing.T) {
+	scanner := &Scanner{
+		config: &ScannerConfig{
+			ForwardLookupDays: 90,
+		},
+	}
+
+	pastDate := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+	futureDate := time.Now().AddDate(1, 0, 0).Format("2006-01-02")
+	cycles := []db.Cycle{
+		{
+			Cycle:        "5",
+			EOL:          toNullString(futureDate),
+			Discontinued: toNullString(pastDate),
+		},
+	}
+
+	result := ComponentResult{Name: "device", Version: "5.1", Status: StatusUnknown}
+	result = scanner.evaluateEOLStatus(result, cycles, "5.1")
+
+	if !result.IsDiscontinued {
+		t.Error("evaluateEOLStatus() IsDiscontinued should be true")
+	}
+	if result.DiscontinuedDate != pastDate {
+		t.Errorf("evaluateEOLStatus() DiscontinuedDate = %s, want %s", result.DiscontinuedDate, pastDate)
+	}
+	if result.Status != StatusActive {
+		t.Errorf("evaluateEOLStatus() status = %s, want %s", result.Status, StatusActive)
+	}
+}
+
+// TestEvaluateEOLStatusDistroVersions tests cycle matching for distro package versions
+func TestEvaluateEOLStatusDistroVersions(t *testing.T) {
+	scanner := &Scanner{
+		config: &ScannerConfig{
+			ForwardLookupDays: 90,
+		},
+	}
+
+	futureDate := time.Now().AddDate(1, 0, 0).Format("2006-01-02")
+	pastDate := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+
+	tests := []struct {
+		name       string
+		pkgType    string
+		version    string
+		cycles     []db.Cycle
+		wantCycle  string
+		wantStatus EOLStatus
+	}{
+		{
+			name:    "debian epoch and revision",
+			pkgType: "deb",
+			version: "1:2.4.57-2+deb12u1",
+			cycles: []db.Cycle{
+				{Cycle: "2.4", EOL: toNullString(futureDate)},
+				{Cycle: "2.2", EOL: toNullString(pastDate)},
+			},
+			wantCycle:  "2.4",
+			wantStatus: StatusActive,
+		},
+		{
+			name:    "apk revision",
+			pkgType: "apk",
+			version: "3.11.4-r0",
+			cycles: []db.Cycle{
+				{Cycle: "3.12", EOL: toNullString(futureDate)},
+				{Cycle: "3.11", EOL: toNullString(pastDate)},
+			},
+			wantCycle:  "3.11",
+			wantStatus: StatusEOL,
+		},
+		{
+			name:    "rpm epoch",
+			pkgType: "rpm",
+			version: "1:3.6.8-51.el8",
+			cycles: []db.Cycle{
+				{Cycle: "3.9", EOL: toNullString(futureDate)},
+				{Cycle: "3.6", EOL: toNullString(pastDate)},
+			},
+			wantCycle:  "3.6",
+			wantStatus: StatusEOL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ComponentResult{Name: "pkg", Version: tt.version, Type: tt.pkgType, Status: StatusUnknown}
+			result = scanner.evaluateEOLStatus(result, tt.cycles, tt.version)
+
+			if result.MatchedCycle != tt.wantCycle {
+				t.Errorf("evaluateEOLStatus() MatchedCycle = %q, want %q", result.MatchedCycle, tt.wantCycle)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("evaluateEOLStatus() status = %s, want %s", result.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// TestSelectCycle tests most-specific, order-independent cycle selection
+func TestSelectCycle(t *testing.T) {
+	cycle := func(name, releaseDate string) db.Cycle {
+		c := db.Cycle{Cycle: name}
+		if releaseDate != "" {
+			c.ReleaseDate = toNullString(releaseDate)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name          string
+		version       string
+		scheme        VersionScheme
+		cycles        []db.Cycle
+		wantCycle     string
+		wantAmbiguous []string
+		wantFallback  bool
+	}{
+		{
+			name:    "java feature release",
+			version: "17.0.9+9",
+			cycles: []db.Cycle{
+				cycle("21", "2023-09-19"), cycle("17", "2021-09-14"), cycle("11", "2018-09-25"), cycle("8", "2014-03-18"),
+			},
+			wantCycle: "17",
+		},
+		{
+			name:    "java does not match by prefix",
+			version: "11.0.21",
+			cycles: []db.Cycle{
+				cycle("21", "2023-09-19"), cycle("17", "2021-09-14"), cycle("11", "2018-09-25"), cycle("1", ""),
+			},
+			wantCycle: "11",
+		},
+		{
+			name:    "ubuntu point release",
+			version: "22.04.3",
+			cycles: []db.Cycle{
+				cycle("24.04", "2024-04-25"), cycle("22.10", "2022-10-20"), cycle("22.04", "2022-04-21"), cycle("20.04", "2020-04-23"),
+			},
+			wantCycle: "22.04",
+		},
+		{
+			name:    "ubuntu interim release",
+			version: "22.10",
+			cycles: []db.Cycle{
+				cycle("24.04", "2024-04-25"), cycle("22.10", "2022-10-20"), cycle("22.04", "2022-04-21"),
+			},
+			wantCycle: "22.10",
+		},
+		{
+			name:    "go nested cycles prefer the most specific",
+			version: "1.21.5",
+			cycles: []db.Cycle{
+				cycle("1", "2012-03-28"), cycle("1.22", "2024-02-06"), cycle("1.21", "2023-08-08"),
+			},
+			wantCycle: "1.21",
+		},
+		{
+			name:    "go nested cycles without release dates",
+			version: "1.21.5",
+			cycles: []db.Cycle{
+				cycle("1.21", ""), cycle("1", ""), cycle("1.2", ""),
+			},
+			wantCycle: "1.21",
+		},
+		{
+			name:    "go falls back to the nested major cycle",
+			version: "1.19.2",
+			cycles: []db.Cycle{
+				cycle("1.22", "2024-02-06"), cycle("1.21", "2023-08-08"), cycle("1", "2012-03-28"),
+			},
+			wantCycle: "1",
+		},
+		{
+			name:    "kubernetes semver with v prefix",
+			version: "v1.28.3",
+			scheme:  SchemeSemver,
+			cycles: []db.Cycle{
+				cycle("1.30", "2024-04-17"), cycle("1.29", "2023-12-13"), cycle("1.28", "2023-08-15"), cycle("1.2", "2016-03-16"),
+			},
+			wantCycle: "1.28",
+		},
+		{
+			name:    "kubernetes major fallback picks the closest lower cycle",
+			version: "1.31.0",
+			cycles: []db.Cycle{
+				cycle("1.28", ""), cycle("1.30", ""), cycle("1.29", ""),
+			},
+			wantCycle:    "1.30",
+			wantFallback: true,
+		},
+		{
+			name:    "major fallback below every cycle leaves the version unmatched",
+			version: "1.10.0",
+			cycles: []db.Cycle{
+				cycle("1.30", ""), cycle("1.29", ""),
+			},
+		},
+		{
+			name:    "equally specific cycles are reported as ambiguous",
+			version: "22.04.3",
+			cycles: []db.Cycle{
+				cycle("22.4", "2022-04-01"), cycle("22.04", "2022-04-21"),
+			},
+			wantCycle:     "22.04",
+			wantAmbiguous: []string{"22.4"},
+		},
+		{
+			name:    "no match",
+			version: "5.0.0",
+			cycles: []db.Cycle{
+				cycle("4.1", ""), cycle("3.9", ""),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := tt.scheme
+			if scheme == "" {
+				scheme = SchemeGeneric
+			}
+			version := ParseVersion(tt.version, scheme)
+
+			// The selection must not depend on the order the database returned the cycles in
+			orders := [][]db.Cycle{tt.cycles, reverseCycles(tt.cycles)}
+			for _, cycles := range orders {
+				sorted := sortCyclesNewestFirst(cycles)
+				index, ambiguous, fallback := selectCycle(version, sorted)
+
+				got := ""
+				if index >= 0 {
+					got = sorted[index].Cycle
+				}
+				if got != tt.wantCycle {
+					t.Fatalf("selectCycle(%q) = %q, want %q", tt.version, got, tt.wantCycle)
+				}
+				if fallback != tt.wantFallback {
+					t.Errorf("selectCycle(%q) fallback = %v, want %v", tt.version, fallback, tt.wantFallback)
+				}
+				if len(ambiguous) != len(tt.wantAmbiguous) {
+					t.Fatalf("selectCycle(%q) ambiguous = %v, want %v", tt.version, ambiguous, tt.wantAmbiguous)
+				}
+				for i := range ambiguous {
+					if ambiguous[i] != tt.wantAmbiguous[i] {
+						t.Errorf("selectCycle(%q) ambiguous = %v, want %v", tt.version, ambiguous, tt.wantAmbiguous)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestSortCyclesNewestFirst tests the deterministic ordering of cycles
+func TestSortCyclesNewestFirst(t *testing.T) {
+	cycles := []db.Cycle{
+		{Cycle: "9"},
+		{Cycle: "11", ReleaseDate: toNullString("2018-09-25")},
+		{Cycle: "10"},
+		{Cycle: "17", ReleaseDate: toNullString("2021-09-14")},
+	}
+
+	sorted := sortCyclesNewestFirst(cycles)
+
+	want := []string{"17", "11", "10", "9"}
+	for i, c := range sorted {
+		if c.Cycle != want[i] {
+			t.Errorf("sortCyclesNewestFirst()[%d] = %s, want %s", i, c.Cycle, want[i])
+		}
+	}
+	if cycles[0].Cycle != "9" {
+		t.Error("sortCyclesNewestFirst() should not modify its input")
+	}
+}
+
+// TestNewMatchInfo tests the provenance and confidence recorded for matches
+func TestNewMatchInfo(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         MatchMethod
+		match          db.LookupMatch
+		pkg            pkg.Package
+		wantMethod     MatchMethod
+		wantConfidence MatchConfidence
+	}{
+		{
+			name:           "exact purl",
+			method:         MatchExactPURL,
+			match:          db.LookupMatch{Method: db.MatchMethodPURL, Identifier: "pkg:pypi/django"},
+			pkg:            pkg.Package{Name: "django", Type: pkg.PythonPkg},
+			wantMethod:     MatchExactPURL,
+			wantConfidence: ConfidenceHigh,
+		},
+		{
+			name:           "versioned purl matched by prefix",
+			method:         MatchExactPURL,
+			match:          db.LookupMatch{Method: db.MatchMethodPURLPrefix, Identifier: "pkg:pypi/django"},
+			pkg:            pkg.Package{Name: "django", Type: pkg.PythonPkg},
+			wantMethod:     MatchPURLPrefix,
+			wantConfidence: ConfidenceHigh,
+		},
+		{
+			name:           "purl prefix hitting a different package",
+			method:         MatchPURLPrefix,
+			match:          db.LookupMatch{Method: db.MatchMethodPURLPrefix, Identifier: "pkg:npm/express-session"},
+			pkg:            pkg.Package{Name: "express", Type: pkg.NpmPkg},
+			wantMethod:     MatchPURLPrefix,
+			wantConfidence: ConfidenceLow,
+		},
+		{
+			name:           "source package purl",
+			method:         MatchSourcePackage,
+			match:          db.LookupMatch{Method: db.MatchMethodPURLPrefix, Identifier: "pkg:deb/debian/openssl"},
+			pkg:            pkg.Package{Name: "libssl3", Type: pkg.DebPkg, Metadata: pkg.DpkgDBEntry{Source: "openssl"}},
+			wantMethod:     MatchSourcePackage,
+			wantConfidence: ConfidenceHigh,
+		},
+		{
+			name:           "source package prefix hitting a different package",
+			method:         MatchSourcePackage,
+			match:          db.LookupMatch{Method: db.MatchMethodPURLPrefix, Identifier: "pkg:deb/debian/openssl-provider-legacy"},
+			pkg:            pkg.Package{Name: "libssl3", Type: pkg.DebPkg, Metadata: pkg.DpkgDBEntry{Source: "openssl"}},
+			wantMethod:     MatchSourcePackage,
+			wantConfidence: ConfidenceMedium,
+		},
+		{
+			name:           "distro purl",
+			method:         MatchDistroPURL,
+			match:          db.LookupMatch{Method: db.MatchMethodPURLPrefix, Identifier: "pkg:deb/debian/nginx"},
+			pkg:            pkg.Package{Name: "nginx", Type: pkg.DebPkg},
+			wantMethod:     MatchDistroPURL,
+			wantConfidence: ConfidenceHigh,
+		},
+		{
+			name:           "cpe prefix",
+			method:         MatchCPE,
+			match:          db.LookupMatch{Method: db.MatchMethodCPEPrefix, Identifier: "cpe:2.3:a:nginx:nginx:*:*:*:*:*:*:*:*"},
+			pkg:            pkg.Package{Name: "nginx", Type: pkg.BinaryPkg},
+			wantMethod:     MatchCPE,
+			wantConfidence: ConfidenceMedium,
+		},
+		{
+			name:           "alias for a distro package",
+			method:         MatchName,
+			match:          db.LookupMatch{Method: db.MatchMethodAlias, Identifier: "postgres"},
+			pkg:            pkg.Package{Name: "postgres", Type: pkg.DebPkg},
+			wantMethod:     MatchAlias,
+			wantConfidence: ConfidenceMedium,
+		},
+		{
+			name:           "product name for an npm package",
+			method:         MatchName,
+			match:          db.LookupMatch{Method: db.MatchMethodName, Identifier: "nginx"},
+			pkg:            pkg.Package{Name: "nginx", Type: pkg.NpmPkg},
+			wantMethod:     MatchName,
+			wantConfidence: ConfidenceLow,
+		},
+		{
+			name:           "repology",
+			method:         MatchName,
+			match:          db.LookupMatch{Method: db.MatchMethodRepology, Identifier: "openssl"},
+			pkg:            pkg.Package{Name: "openssl", Type: pkg.ApkPkg},
+			wantMethod:     MatchRepology,
+			wantConfidence: ConfidenceMedium,
+		},
+		{
+			name:   "exact purl tied with another product",
+			method: MatchExactPURL,
+			match: db.LookupMatch{Method: db.MatchMethodPURL, Identifier: "pkg:generic/zulu", Ambiguous: true,
+				Alternatives: []db.Product{{Name: "zulu", Match: &db.LookupMatch{Identifier: "pkg:generic/zulu"}}}},
+			pkg:            pkg.Package{Name: "zulu", Type: pkg.BinaryPkg},
+			wantMethod:     MatchExactPURL,
+			wantConfidence: ConfidenceMedium,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := newMatchInfo(tt.method, &tt.match, tt.pkg)
+			if len(info.Alternatives) != len(tt.match.Alternatives) || info.Ambiguous != tt.match.Ambiguous {
+				t.Errorf("newMatchInfo() Alternatives = %v (ambiguous %v), want %d (ambiguous %v)",
+					info.Alternatives, info.Ambiguous, len(tt.match.Alternatives), tt.match.Ambiguous)
+			}
+			if info.Method != tt.wantMethod {
+				t.Errorf("newMatchInfo() Method = %s, want %s", info.Method, tt.wantMethod)
+			}
+			if info.Identifier != tt.match.Identifier {
+				t.Errorf("newMatchInfo() Identifier = %s, want %s", info.Identifier, tt.match.Identifier)
+			}
+			if info.Confidence != tt.wantConfidence {
+				t.Errorf("newMatchInfo() Confidence = %s, want %s", info.Confidence, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+// TestPURLName tests extracting the package name from PURLs
+func TestPURLName(t *testing.T) {
+	tests := map[string]string{
+		"pkg:npm/express":                        "express",
+		"pkg:npm/%40angular/core@17.0.0":         "core",
+		"pkg:deb/debian/nginx@1.22.1?arch=amd64": "nginx",
+		"pkg:generic/postgresql":                 "postgresql",
+	}
+
+	for purl, want := range tests {
+		if got := purlName(purl); got != want {
+			t.Errorf("purlName(%q) = %q, want %q", purl, got, want)
+		}
+	}
+}
+
+// TestMatchSteps tests the order and applicability of the lookup chain
+func TestMatchSteps(t *testing.T) {
+	scanner := &Scanner{config: DefaultScannerConfig()}
+
+	steps := scanner.matchSteps(pkg.Package{
+		Name: "nginx",
+		Type: pkg.DebPkg,
+		PURL: "pkg:deb/debian/nginx@1.22.1-9",
+	})
+
+	want := []struct {
+		method  MatchMethod
+		query   string
+		skipped bool
+	}{
+		{MatchExactPURL, "pkg:deb/debian/nginx@1.22.1-9", false},
+		{MatchRuntime, "", true},
+		{MatchMaven, "", true},
+		{MatchVersionedName, "", true},
+		{MatchDistroPURL, "pkg:deb/debian/nginx", false},
+		{MatchDistroPURL, "pkg:deb/ubuntu/nginx", false},
+		{MatchSourcePackage, "", true},
+		{MatchPURLPrefix, "pkg:deb/nginx", false},
+		{MatchPURLPrefix, "pkg:generic/nginx", false},
+		{MatchCPE, "", true},
+		{MatchName, "nginx", false},
+	}
+
+	if len(steps) != len(want) {
+		t.Fatalf("matchSteps() returned %d steps, want %d", len(steps), len(want))
+	}
+	for i, w := range want {
+		if steps[i].Method != w.method || steps[i].Query != w.query {
+			t.Errorf("step %d = %s %q, want %s %q", i, steps[i].Method, steps[i].Query, w.method, w.query)
+		}
+		if (steps[i].Lookup == nil) != w.skipped {
+			t.Errorf("step %d skipped = %v, want %v", i, steps[i].Lookup == nil, w.skipped)
+		}
+	}
+}
+
+// TestScanSummaryAddComponent tests that addComponent keeps the status counts in sync
+func TestScanSummaryAddComponent(t *testing.T) {
+	summary := &ScanSummary{}
+
+	for _, status := range []EOLStatus{StatusEOL, StatusEOLSoon, StatusSecurityOnly, StatusSecurityOnly, StatusActive, StatusUnknown} {
+		summary.addComponent(ComponentResult{Name: "pkg", Status: status})
+	}
+
+	if summary.TotalComponents != 6 {
+		t.Errorf("TotalComponents = %d, want 6", summary.TotalComponents)
+	}
+	if summary.SecurityOnlyComponents != 2 {
+		t.Errorf("SecurityOnlyComponents = %d, want 2", summary.SecurityOnlyComponents)
+	}
+	if summary.EOLComponents != 1 || summary.EOLSoonComponents != 1 || summary.ActiveComponents != 1 || summary.UnknownComponents != 1 {
+		t.Errorf("unexpected counts: eol=%d soon=%d active=%d unknown=%d",
+			summary.EOLComponents, summary.EOLSoonComponents, summary.ActiveComponents, summary.UnknownComponents)
+	}
+	if len(summary.Components) != 6 {
+		t.Errorf("len(Components) = %d, want 6", len(summary.Components))
+	}
+
+	summary.addComponent(ComponentResult{Name: "node", Status: StatusActive, OutdatedInCycle: true})
+	if summary.OutdatedInCycleComponents != 1 {
+		t.Errorf("OutdatedInCycleComponents = %d, want 1", summary.OutdatedInCycleComponents)
+	}
+}
+
+// TestScanSummaryRollingOS tests that a rolling OS release has its own status bucket, so
+// the buckets add up to the totals
+func TestScanSummaryRollingOS(t *testing.T) {
+	summary := &ScanSummary{}
+	summary.addComponent(ComponentResult{Name: "Arch Linux", Type: "os", Status: StatusRolling, MatchedProduct: "arch-linux"})
+	summary.addComponent(ComponentResult{Name: "python", Status: StatusActive, MatchedProduct: "python", MatchedCycle: "3.12"})
+	summary.addComponent(ComponentResult{Name: "libfoo", Status: StatusUnknown})
+	for _, finding := range GroupFindings(summary.Components) {
+		summary.addFinding(finding)
+	}
+
+	if summary.RollingComponents != 1 || summary.RollingFindings != 1 {
+		t.Errorf("RollingComponents = %d, RollingFindings = %d, want 1 and 1", summary.RollingComponents, summary.RollingFindings)
+	}
+	components := summary.EOLComponents + summary.EOLSoonComponents + summary.SecurityOnlyComponents +
+		summary.ActiveComponents + summary.RollingComponents + summary.UnknownComponents
+	if components != summary.TotalComponents {
+		t.Errorf("component buckets sum to %d, want %d", components, summary.TotalComponents)
+	}
+	findings := summary.EOLFindings + summary.EOLSoonFindings + summary.SecurityOnlyFindings +
+		summary.ActiveFindings + summary.RollingFindings
+	if findings != summary.TotalFindings {
+		t.Errorf("finding buckets sum to %d, want %d", findings, summary.TotalFindings)
+	}
+}
+
+// TestEvaluateEOLStatusPatchLevel tests detection of components behind the latest release of their cycle
+func TestEvaluateEOLStatusPatchLevel(t *testing.T) {
+	scanner := &Scanner{
+		config: &ScannerConfig{
+			ForwardLookupDays: 90,
+		},
+	}
+
+	futureDate := time.Now().AddDate(1, 0, 0).Format("2006-01-02")
+	latestReleaseDate := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
+
+	tests := []struct {
+		name         string
+		pkgType      string
+		version      string
+		latest       string
+		wantOutdated bool
+		wantBehind   int
+	}{
+		{name: "minor releases behind", version: "20.1.0", latest: "20.18.0", wantOutdated: true, wantBehind: 17},
+		{name: "patch releases behind", version: "20.18.0", latest: "20.18.3", wantOutdated: true, wantBehind: 3},
+		{name: "up to date", version: "20.18.3", latest: "20.18.3"},
+		{name: "newer than latest", version: "20.19.0", latest: "20.18.3"},
+		{name: "distro revision ignored", version: "20.18.3-1ubuntu1", latest: "20.18.3"},
+		{name: "distro revision on outdated version", version: "20.17.0-1nodesource1", latest: "20.18.0", wantOutdated: true, wantBehind: 1},
+		{name: "missing patch segment", version: "20.18", latest: "20.18.2", wantOutdated: true, wantBehind: 2},
+		{name: "bare cycle has no patch level", version: "20", latest: "20.18.2"},
+		{name: "deb epoch ignored", pkgType: "deb", version: "1:20.17.0-2+deb12u1", latest: "20.18.2", wantOutdated: true, wantBehind: 1},
+		{name: "deb epoch on up to date version", pkgType: "deb", version: "1:20.18.2-1", latest: "20.18.2"},
+		{name: "deb packaging suffix ignored", pkgType: "deb", version: "20.18.2~dfsg-1", latest: "20.18.2"},
+		{name: "rpm revision ignored", pkgType: "rpm", version: "20.18.2-1.el9", latest: "20.18.2"},
+		{name: "rpm revision on outdated version", pkgType: "rpm", version: "20.16.0-1.el9", latest: "20.18.2", wantOutdated: true, wantBehind: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cycles := []db.Cycle{
+				{
+					Cycle:             "20",
+					EOL:               toNullString(futureDate),
+					LatestVersion:     toNullString(tt.latest),
+					LatestReleaseDate: toNullString(latestReleaseDate),
+				},
+			}
+
+			result := ComponentResult{Name: "node", Version: tt.version, Type: tt.pkgType, Status: StatusUnknown}
+			result = scanner.evaluateEOLStatus(result, cycles, tt.version)
+
+			if result.OutdatedInCycle != tt.wantOutdated {
+				t.Fatalf("evaluateEOLStatus() OutdatedInCycle = %v, want %v", result.OutdatedInCycle, tt.wantOutdated)
+			}
+			if result.Status != StatusActive {
+				t.Errorf("evaluateEOLStatus() status = %s, want %s", result.Status, StatusActive)
+			}
+			if !tt.wantOutdated {
+				if result.ReleasesBehind != nil || result.DaysSinceLatestRelease != nil {
+					t.Error("evaluateEOLStatus() should not report lag for an up to date component")
+				}
+				return
+			}
+			if result.ReleasesBehind == nil || *result.ReleasesBehind != tt.wantBehind {
+				t.Errorf("evaluateEOLStatus() ReleasesBehind = %v, want %d", result.ReleasesBehind, tt.wantBehind)
+			}
+			if result.DaysSinceLatestRelease == nil || *result.DaysSinceLatestRelease < 29 || *result.DaysSinceLatestRelease > 30 {
+				t.Errorf("evaluateEOLStatus() DaysSinceLatestRelease = %v, want ~30", result.DaysSinceLatestRelease)
+			}
+			if result.LatestReleaseDate != latestReleaseDate {
+				t.Errorf("evaluateEOLStatus() LatestReleaseDate = %s, want %s", result.LatestReleaseDate, latestReleaseDate)
+			}
+		})
+	}
+}
+
+// TestEvaluatePatchLevelDistroVersion tests that the epoch and revision of a distro
+// package do not hide that it is behind the upstream release
+func TestEvaluatePatchLevelDistroVersion(t *testing.T) {
+	cycle := &db.Cycle{Cycle: "2.4", LatestVersion: toNullString("2.4.62")}
+	installed := ParseVersion("1:2.4.57-2+deb12u1", SchemeDebian)
+
+	result := evaluatePatchLevel(ComponentResult{Name: "apache2"}, cycle, installed, time.Now())
+	if !result.OutdatedInCycle {
+		t.Fatal("evaluatePatchLevel() OutdatedInCycle = false, want true")
+	}
+	if result.ReleasesBehind == nil || *result.ReleasesBehind != 5 {
+		t.Errorf("evaluatePatchLevel() ReleasesBehind = %v, want 5", result.ReleasesBehind)
+	}
+}
+
+// TestEvaluateEOLStatusRecommendation tests upgrade recommendations for EOL components
+func TestEvaluateEOLStatusRecommendation(t *testing.T) {
+	scanner := &Scanner{
+		config: &ScannerConfig{
+			ForwardLookupDays: 90,
+		},
+	}
+
+	pastDate := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+	soonDate := time.Now().AddDate(0, 0, 30).Format("2006-01-02")
+	nearDate := time.Now().AddDate(1, 0, 0).Format("2006-01-02")
+	farDate := time.Now().AddDate(3, 0, 0).Format("2006-01-02")
+
+	type target struct {
+		strategy RecommendationStrategy
+		cycle    string
+		version  string
+		eolDate  string
+	}
+	tests := []struct {
+		name    string
+		cycles  []db.Cycle
+		version string
+		want    []target
+	}{
+		{
+			name: "newest LTS preferred when product has LTS cycles",
+			cycles: []db.Cycle{
+				{Cycle: "23", EOL: toNullString(nearDate)},
+				{Cycle: "22", EOL: toNullString(farDate), LTS: 1, LatestVersion: toNullString("22.11.0")},
+				{Cycle: "21", EOL: toNullString(pastDate)},
+				{Cycle: "20", EOL: toNullString(nearDate), LTS: 1},
+				{Cycle: "16", EOL: toNullString(pastDate), LTS: 1},
+			},
+			version: "16.20.2",
+			want:    []target{{StrategyNewestLTS, "22", "22.11.0", farDate}},
+		},
+		{
+			name: "nearest maintained cycle without LTS",
+			cycles: []db.Cycle{
+				{Cycle: "3.13", EOL: toNullString(farDate)},
+				{Cycle: "3.12", EOL: toNullString(nearDate)},
+				{Cycle: "3.11", EOL: toNullString(soonDate)},
+				{Cycle: "3.8", EOL: toNullString(pastDate)},
+			},
+			version: "3.8.18",
+			want:    []target{{StrategyNearestMaintained, "3.12", "", nearDate}},
+		},
+		{
+			name: "no same-cycle patch recommended for an outdated component",
+			cycles: []db.Cycle{
+				{Cycle: "2", EOL: toNullString(soonDate), LatestVersion: toNullString("2.9.0")},
+			},
+			version: "2.1.0",
+		},
+		{
+			name: "no recommendation when nothing is maintained",
+			cycles: []db.Cycle{
+				{Cycle: "2", EOL: toNullString(pastDate), LatestVersion: toNullString("2.9.0")},
+				{Cycle: "1", EOL: toNullString(pastDate)},
+			},
+			version: "1.4.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ComponentResult{Name: "pkg", Version: tt.version, Status: StatusUnknown}
+			result = scanner.evaluateEOLStatus(result, tt.cycles, tt.version)
+
+			if len(result.Recommendations) != len(tt.want) {
+				t.Fatalf("evaluateEOLStatus() Recommendations = %+v, want %d", result.Recommendations, len(tt.want))
+			}
+			for i, w := range tt.want {
+				r := result.Recommendations[i]
+				if r.Strategy != w.strategy || r.Cycle != w.cycle || r.Version != w.version {
+					t.Errorf("recommendation %d = %s %s (%s), want %s %s (%s)", i, r.Strategy, r.Cycle, r.Version, w.strategy, w.cycle, w.version)
+				}
+				if r.EOLDate != w.eolDate || r.DaysUntilEOL == nil {
+					t.Errorf("recommendation %d EOL = %s (%v days), want %s", i, r.EOLDate, r.DaysUntilEOL, w.eolDate)
+				}
+			}
+		})
+	}
+}
+
+// TestNewRecommendationPastEOL tests that a target past its EOL reports no days of support
+func TestNewRecommendationPastEOL(t *testing.T) {
+	pastDate := time.Now().AddDate(0, 0, -10).Format("2006-01-02")
+	cycle := &db.Cycle{Cycle: "3.8", EOL: toNullString(pastDate)}
+
+	recommendation := newRecommendation(StrategyNearestMaintained, cycle, time.Now())
+	if recommendation.EOLDate != pastDate {
+		t.Errorf("newRecommendation() EOLDate = %s, want %s", recommendation.EOLDate, pastDate)
+	}
+	if recommendation.DaysUntilEOL != nil {
+		t.Errorf("newRecommendation() DaysUntilEOL = %d, want nil", *recommendation.DaysUntilEOL)
+	}
+}
+
+// TestEvaluateEOLStatusNoRecommendationWhenActive tests that active components get no recommendation
+func TestEvaluateEOLStatusNoRecommendationWhenActive(t *testing.T) {
+	scanner := &Scanner{
+		config: &ScannerConfig{
+			ForwardLookupDays: 90,
+		},
+	}
+
+	farDate := time.Now().AddDate(3, 0, 0).Format("2006-01-02")
+	cycles := []db.Cycle{
+		{Cycle: "22", EOL: toNullString(farDate), LTS: 1},
+		{Cycle: "20", EOL: toNullString(farDate), LTS: 1},
+	}
+
+	result := ComponentResult{Name: "node", Version: "20.1.0", Status: StatusUnknown}
+	result = scanner.evaluateEOLStatus(result, cycles, "20.1.0")
+
+	if result.Recommendations != nil {
+		t.Errorf("evaluateEOLStatus() Recommendations = %+v, want nil", result.Recommendations)
+	}
+}
+
+// TestEvaluateEOLStatusCycleFallback tests that a cycle guessed from the major version
+// is flagged and lowers the match confidence
+func TestEvaluateEOLStatusCycleFallback(t *testing.T) {
+	scanner := &Scanner{config: &ScannerConfig{ForwardLookupDays: 90}}
+	farDate := time.Now().AddDate(3, 0, 0).Format("2006-01-02")
+	cycles := []db.Cycle{
+		{Cycle: "1.30", EOL: toNullString(farDate)},
+		{Cycle: "1.29", EOL: toNullString(farDate)},
+	}
+
+	result := ComponentResult{Name: "kubelet", Version: "1.31.0", Status: StatusUnknown,
+		Match: &MatchInfo{Method: MatchExactPURL, Confidence: ConfidenceHigh}}
+	result = scanner.evaluateEOLStatus(result, cycles, "1.31.0")
+	if result.MatchedCycle != "1.30" || !result.CycleFallback || result.Match.Confidence != ConfidenceLow {
+		t.Errorf("fallback = cycle %q (fallback %v, %s confidence), want 1.30 flagged with low confidence",
+			result.MatchedCycle, result.CycleFallback, result.Match.Confidence)
+	}
+
+	result = ComponentResult{Name: "kubelet", Version: "1.30.2", Status: StatusUnknown,
+		Match: &MatchInfo{Method: MatchExactPURL, Confidence: ConfidenceHigh}}
+	result = scanner.evaluateEOLStatus(result, cycles, "1.30.2")
+	if result.CycleFallback || result.Match.Confidence != ConfidenceHigh {
+		t.Errorf("exact cycle = fallback %v, %s confidence, want no fallback with high confidence",
+			result.CycleFallback, result.Match.Confidence)
+	}
+}
+
 // TestNewScannerWithNilConfig tests NewScanner with nil config
 func TestNewScannerWithNilConfig(t *testing.T) {
 	scanner, err := NewScanner(nil)
@@ -795,6 +1549,7 @@ func TestScannerClose(t *testing.T) {
 }
 
 // Helper functions for creating nullable types
+
 func toNullInt64(v int64) sql.NullInt64 {
 	return sql.NullInt64{Int64: v, Valid: true}
 }
@@ -802,3 +1557,189 @@ func toNullInt64(v int64) sql.NullInt64 {
 func toNullString(v string) sql.NullString {
 	return sql.NullString{String: v, Valid: true}
 }
+
+func reverseCycles(cycles []db.Cycle) []db.Cycle {
+	reversed := make([]db.Cycle, len(cycles))
+	for i, c := range cycles {
+		reversed[len(cycles)-1-i] = c
+	}
+	return reversed
+}
+
+// syntheticProducts returns products with PURL, CPE and repology identifiers and
+// three cycles each, plus Debian for the OS
+func syntheticProducts(n int) []db.ProductData {
+	eol := false
+	products := []db.ProductData{{Name: "debian", Category: "os", Releases: []db.ReleaseData{
+		{Name: "12", Codename: "Bookworm", ReleaseDate: "2023-06-10", IsEol: &eol, EolFrom: "2099-06-10"},
+	}}}
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("product-%d", i)
+		var releases []db.ReleaseData
+		for cycle := 1; cycle <= 3; cycle++ {
+			releases = append(releases, db.ReleaseData{
+				Name:        fmt.Sprint(cycle),
+				ReleaseDate: fmt.Sprintf("202%d-01-01", cycle),
+				IsEol:       &eol,
+				EolFrom:     "2099-01-01",
+			})
+		}
+		products = append(products, db.ProductData{
+			Name:     name,
+			Category: []string{"framework", "server-app", "database"}[i%3],
+			Aliases:  []string{name + "-alias"},
+			Identifiers: []db.Identifier{
+				{Type: "purl", ID: "pkg:npm/" + name},
+				{Type: "cpe", ID: fmt.Sprintf("cpe:2.3:a:vendor-%d:%s", i, name)},
+				{Type: "repology", ID: name + "-project"},
+			},
+			Releases: releases,
+		})
+	}
+	return products
+}
+
+// syntheticSBOM returns a Debian image SBOM of n packages matched by PURL, by name, by
+// CPE, or not at all
+func syntheticSBOM(n, products int) *sbom.SBOM {
+	var packages []pkg.Package
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("product-%d", i%products)
+		version := fmt.Sprintf("2.%d.0", i)
+		p := pkg.Package{Name: name, Version: version}
+		switch i % 4 {
+		case 0:
+			p.Type = pkg.NpmPkg
+			p.PURL = "pkg:npm/" + name + "@" + version
+		case 1:
+			p.Type = pkg.DebPkg
+			p.PURL = "pkg:deb/debian/" + name + "@" + version + "?distro=debian-12"
+		case 2:
+			p.Type = pkg.BinaryPkg
+			p.CPEs = []cpe.CPE{cpe.Must(fmt.Sprintf("cpe:2.3:a:vendor-%d:%s:%s:*:*:*:*:*:*:*", i%products, name, version), cpe.GeneratedSource)}
+		default:
+			p.Name = fmt.Sprintf("unknown-%d", i)
+			p.Type = pkg.NpmPkg
+			p.PURL = "pkg:npm/" + p.Name + "@" + version
+		}
+		packages = append(packages, p)
+	}
+	return &sbom.SBOM{Artifacts: sbom.Artifacts{
+		Packages:          pkg.NewCollection(packages...),
+		LinuxDistribution: &linux.Release{ID: "debian", VersionID: "12"},
+	}}
+}
+
+// TestAnalyzeSBOMIndex tests that scans served by the in-memory index match scans
+// querying the database
+func TestAnalyzeSBOMIndex(t *testing.T) {
+	scanner := newTestDBScanner(t, syntheticProducts(20)...)
+	scanner.config.SuggestProducts = true
+	sbomResult := syntheticSBOM(200, 20)
+
+	scanner.lookup = scanner.dbManager
+	want, err := scanner.analyzeSBOM(sbomResult, "synthetic")
+	if err != nil {
+		t.Fatalf("analyzeSBOM() error = %v", err)
+	}
+	scanner.lookup = nil
+	got, err := scanner.analyzeSBOM(sbomResult, "synthetic")
+	if err != nil {
+		t.Fatalf("analyzeSBOM() error = %v", err)
+	}
+	if _, ok := scanner.lookup.(*db.Index); !ok {
+		t.Fatalf("lookups served by %T, want *db.Index", scanner.lookup)
+	}
+
+	if len(got.Components) != len(want.Components) {
+		t.Fatalf("index scan has %d components, database scan %d", len(got.Components), len(want.Components))
+	}
+	for i, component := range got.Components {
+		w := want.Components[i]
+		if component.Name != w.Name || component.MatchedProduct != w.MatchedProduct ||
+			component.MatchedCycle != w.MatchedCycle || component.Status != w.Status {
+			t.Errorf("component %s: index matched %s %s (%s), database %s %s (%s)", component.Name,
+				component.MatchedProduct, component.MatchedCycle, component.Status,
+				w.MatchedProduct, w.MatchedCycle, w.Status)
+		}
+	}
+	if got.EOLComponents != want.EOLComponents || got.UnknownComponents != want.UnknownComponents {
+		t.Errorf("index summary = %d EOL, %d unknown; database = %d EOL, %d unknown",
+			got.EOLComponents, got.UnknownComponents, want.EOLComponents, want.UnknownComponents)
+	}
+	if got.SuggestionsUnavailable || !want.SuggestionsUnavailable {
+		t.Errorf("SuggestionsUnavailable = %v with the index, %v with the database, want false and true",
+			got.SuggestionsUnavailable, want.SuggestionsUnavailable)
+	}
+}
+
+// BenchmarkAnalyzeSBOM compares scanning large synthetic SBOMs with lookups served by
+// the in-memory index and by database queries
+func BenchmarkAnalyzeSBOM(b *testing.B) {
+	scanner := newTestDBScanner(b, syntheticProducts(500)...)
+
+	for _, size := range []int{1000, 5000} {
+		sbomResult := syntheticSBOM(size, 500)
+		for _, mode := range []string{"index", "database"} {
+			b.Run(fmt.Sprintf("packages=%d/%s", size, mode), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					// The index is loaded by the first lookup of each scan
+					scanner.lookup = nil
+					if mode == "database" {
+						scanner.lookup = scanner.dbManager
+					}
+					if _, err := scanner.analyzeSBOM(sbomResult, "synthetic"); err != nil {
+						b.Fatalf("analyzeSBOM() error = %v", err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestAnalyzeSBOMCollapsedCounts tests that binaries collapsed into one source package
+// finding are still counted as packages in the summary
+func TestAnalyzeSBOMCollapsedCounts(t *testing.T) {
+	eol := true
+	products := append(osProducts(), db.ProductData{
+		Name:        "openssl",
+		Category:    "lang",
+		Identifiers: []db.Identifier{{Type: "purl", ID: "pkg:deb/debian/openssl"}},
+		Releases: []db.ReleaseData{
+			{Name: "1.1.1", ReleaseDate: "2018-09-11", IsEol: &eol, EolFrom: "2023-09-11"},
+		},
+	})
+	scanner := newTestDBScanner(t, products...)
+
+	var packages []pkg.Package
+	for _, name := range []string{"libssl1.1", "libssl-dev", "openssl"} {
+		packages = append(packages, pkg.Package{
+			Name:     name,
+			Version:  "1.1.1n-0+deb11u5",
+			Type:     pkg.DebPkg,
+			PURL:     "pkg:deb/debian/" + name + "@1.1.1n-0+deb11u5?distro=debian-12",
+			Metadata: pkg.DpkgDBEntry{Package: name, Source: "openssl"},
+		})
+	}
+	summary, err := scanner.analyzeSBOM(&sbom.SBOM{Artifacts: sbom.Artifacts{
+		Packages:          pkg.NewCollection(packages...),
+		LinuxDistribution: &linux.Release{ID: "debian", VersionID: "12"},
+	}}, "collapsed")
+	if err != nil {
+		t.Fatalf("analyzeSBOM() error = %v", err)
+	}
+
+	// Debian and the three OpenSSL packages, reported as Debian and one OpenSSL result
+	if len(summary.Components) != 2 {
+		t.Fatalf("len(Components) = %d, want 2", len(summary.Components))
+	}
+	if want := []string{"libssl-dev", "libssl1.1", "openssl"}; !slices.Equal(slices.Sorted(slices.Values(summary.Components[1].Binaries)), want) {
+		t.Errorf("OpenSSL binaries = %v, want %v", summary.Components[1].Binaries, want)
+	}
+	if summary.TotalComponents != 4 {
+		t.Errorf("TotalComponents = %d, want 4", summary.TotalComponents)
+	}
+	if summary.EOLComponents != 3 {
+		t.Errorf("EOLComponents = %d, want 3", summary.EOLComponents)
+	}
+}
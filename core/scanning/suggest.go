@@ -0,0 +1,118 @@
+package scanning
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/j0356/eol-scanner/core/db"
+)
+
+// Suggestion is a product resembling a component that no lookup matched
+type Suggestion struct {
+	Product string  `json:"product"`
+	Score   float64 `json:"score"`   // Resemblance from 0 to 1
+	Matched string  `json:"matched"` // Name, alias or identifier that resembled the component
+}
+
+// maxSuggestions is the number of products suggested for an unmatched component
+const maxSuggestions = 3
+
+// suggestProducts returns the products whose names, aliases and identifiers best
+// resemble the name and vendor of an unmatched package
+func (s *Scanner) suggestProducts(p pkg.Package) []Suggestion {
+	index, ok := s.lookups().(*db.Index)
+	if !ok {
+		return nil
+	}
+	var suggestions []Suggestion
+	for _, suggestion := range index.Suggest(p.Name, suggestVendor(p), maxSuggestions) {
+		suggestions = append(suggestions, Suggestion{
+			Product: suggestion.Product,
+			Score:   math.Round(suggestion.Score*100) / 100,
+			Matched: suggestion.Matched,
+		})
+	}
+	return suggestions
+}
+
+// suggestVendor returns who publishes a package: its distributor, the vendor of its
+// first CPE, or its PURL namespace
+func suggestVendor(p pkg.Package) string {
+	if vendor := packageVendor(p); vendor != "" {
+		return vendor
+	}
+	for _, c := range p.CPEs {
+		if c.Attributes.Vendor != "" && c.Attributes.Vendor != "*" {
+			return c.Attributes.Vendor
+		}
+	}
+	if purl, err := db.ParsePURL(p.PURL); err == nil {
+		return purl.Namespace
+	}
+	return ""
+}
+
+// WriteSuggestedMappings writes a mapping file for the unmatched components of a scan.
+// Components with suggestions get a mapping to the best suggested product, with the
+// others listed in a comment; components without are listed at the end. It returns
+// the number of mappings written.
+func WriteSuggestedMappings(w io.Writer, components []ComponentResult) (int, error) {
+	var b strings.Builder
+	b.WriteString("# Suggested mappings for components that no lookup matched.\n")
+	b.WriteString("# Check each product against the alternatives, fix or delete the entry and pass\n")
+	b.WriteString("# the file to --mappings. Scores range from 0 to 1.\n")
+	b.WriteString("mappings:\n")
+
+	written := 0
+	seen := make(map[string]bool)
+	var unmatched []string
+	for _, c := range components {
+		if c.MatchedProduct != "" || c.Type == "os" {
+			continue
+		}
+		key := c.Type + "/" + c.Name
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if len(c.Suggestions) == 0 {
+			unmatched = append(unmatched, fmt.Sprintf("%s %s (%s)", c.Name, c.Version, c.Type))
+			continue
+		}
+
+		best := c.Suggestions[0]
+		fmt.Fprintf(&b, "  # %s %s (%s): %s, score %.2f via %q\n", c.Name, c.Version, c.Type, best.Product, best.Score, best.Matched)
+		for _, alternative := range c.Suggestions[1:] {
+			fmt.Fprintf(&b, "  #   or %s, score %.2f via %q\n", alternative.Product, alternative.Score, alternative.Matched)
+		}
+		fmt.Fprintf(&b, "  - name: %s\n", strconv.Quote(escapeGlob(c.Name)))
+		if c.Type != "" {
+			fmt.Fprintf(&b, "    type: %s\n", c.Type)
+		}
+		fmt.Fprintf(&b, "    product: %s\n", strconv.Quote(best.Product))
+		written++
+	}
+	if written == 0 {
+		b.WriteString("  []\n")
+	}
+
+	if len(unmatched) > 0 {
+		b.WriteString("\n# No product resembles these components:\n")
+		for _, component := range unmatched {
+			fmt.Fprintf(&b, "#   %s\n", component)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return written, err
+}
+
+// escapeGlob escapes the glob metacharacters of a package name for a name selector
+func escapeGlob(name string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`).Replace(name)
+}
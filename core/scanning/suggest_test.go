@@ -0,0 +1,129 @@
+package scanning
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/j0356/eol-scanner/core/db"
+)
+
+// TestCheckComponentSuggestions tests that only unmatched components get suggestions,
+// and only when asked for
+func TestCheckComponentSuggestions(t *testing.T) {
+	scanner := newTestDBScanner(t,
+		db.ProductData{Name: "postgresql", Label: "PostgreSQL", Category: "database"},
+		db.ProductData{Name: "nodejs", Label: "Node.js", Category: "lang"},
+	)
+
+	result := scanner.checkComponent(pkg.Package{Name: "acme-postgresql-client", Version: "15.4", Type: pkg.BinaryPkg})
+	if result.Suggestions != nil {
+		t.Errorf("suggestions without SuggestProducts = %+v, want none", result.Suggestions)
+	}
+
+	scanner.config.SuggestProducts = true
+	result = scanner.checkComponent(pkg.Package{Name: "acme-postgresql-client", Version: "15.4", Type: pkg.BinaryPkg})
+	if result.Status != StatusUnknown || len(result.Suggestions) == 0 || result.Suggestions[0].Product != "postgresql" {
+		t.Errorf("unmatched component suggestions = %+v, want postgresql first", result.Suggestions)
+	}
+
+	result = scanner.checkComponent(pkg.Package{Name: "postgresql", Version: "15.4", Type: pkg.BinaryPkg})
+	if result.MatchedProduct != "postgresql" || result.Suggestions != nil {
+		t.Errorf("matched component = %s with suggestions %+v, want postgresql without suggestions", result.MatchedProduct, result.Suggestions)
+	}
+}
+
+// TestSuggestVendor tests where the vendor of an unmatched package is read from
+func TestSuggestVendor(t *testing.T) {
+	tests := []struct {
+		name string
+		p    pkg.Package
+		want string
+	}{
+		{"purl namespace", pkg.Package{Name: "acme-boot", PURL: "pkg:maven/com.acme.boot/acme-boot@1.0"}, "com.acme.boot"},
+		{"no vendor", pkg.Package{Name: "acme-tool", PURL: "pkg:generic/acme-tool@1.0"}, ""},
+		{"no purl", pkg.Package{Name: "acme-tool"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suggestVendor(tt.p); got != tt.want {
+				t.Errorf("suggestVendor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWriteSuggestedMappings tests that suggestions are written as a mapping file that
+// loads as is
+func TestWriteSuggestedMappings(t *testing.T) {
+	components := []ComponentResult{
+		{Name: "Debian GNU/Linux 12", Type: "os", Status: StatusUnknown},
+		{Name: "acme-postgresql-client", Version: "15.4", Type: "binary", Status: StatusUnknown, Suggestions: []Suggestion{
+			{Product: "postgresql", Score: 0.67, Matched: "postgresql"},
+			{Product: "postgis", Score: 0.52, Matched: "postgis"},
+		}},
+		{Name: "acme-postgresql-client", Version: "15.5", Type: "binary", Status: StatusUnknown, Suggestions: []Suggestion{
+			{Product: "postgresql", Score: 0.67, Matched: "postgresql"},
+		}},
+		{Name: "weird[name]", Type: "npm", Status: StatusUnknown, Suggestions: []Suggestion{
+			{Product: "nodejs", Score: 0.5, Matched: "node"},
+		}},
+		{Name: "zlib1g", Version: "1.2.13", Type: "deb", Status: StatusUnknown},
+		{Name: "nginx", Type: "deb", Status: StatusActive, MatchedProduct: "nginx"},
+	}
+
+	var b strings.Builder
+	written, err := WriteSuggestedMappings(&b, components)
+	if err != nil {
+		t.Fatalf("WriteSuggestedMappings() error = %v", err)
+	}
+	if written != 2 {
+		t.Errorf("WriteSuggestedMappings() wrote %d mappings, want 2", written)
+	}
+	output := b.String()
+	for _, want := range []string{
+		`#   or postgis, score 0.52 via "postgis"`,
+		"#   zlib1g 1.2.13 (deb)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q:\n%s", want, output)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "suggested.yaml")
+	if err := os.WriteFile(path, []byte(output), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadMappings(path)
+	if err != nil {
+		t.Fatalf("LoadMappings() error = %v\n%s", err, output)
+	}
+	if len(config.Mappings) != 2 {
+		t.Fatalf("loaded %d mappings, want 2", len(config.Mappings))
+	}
+	if m := config.Mappings[0]; m.Name != "acme-postgresql-client" || m.Type != "binary" || m.Product != "postgresql" {
+		t.Errorf("first mapping = %+v, want acme-postgresql-client (binary) → postgresql", m)
+	}
+	if config.findMapping(pkg.Package{Name: "weird[name]", Type: pkg.NpmPkg}) == nil {
+		t.Error("mapping of weird[name] does not match the package")
+	}
+}
+
+// TestWriteSuggestedMappingsEmpty tests that a scan without suggestions still writes a
+// valid mapping file
+func TestWriteSuggestedMappingsEmpty(t *testing.T) {
+	var b strings.Builder
+	if _, err := WriteSuggestedMappings(&b, nil); err != nil {
+		t.Fatalf("WriteSuggestedMappings() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "suggested.yaml")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMappings(path); err != nil {
+		t.Errorf("LoadMappings() error = %v\n%s", err, b.String())
+	}
+}
@@ -0,0 +1,89 @@
+package scanning
+
+import (
+	"sort"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// goToolchain is a Go compiler version and the binaries in the image built with it
+type goToolchain struct {
+	version   string
+	binaries  []string
+	locations []file.Location
+}
+
+// goToolchains collects the compiler versions of the Go binaries in an SBOM. Syft
+// records the compiler version in the build info of every module of a binary; the
+// binaries are told apart by their location. Toolchains are ordered by version.
+func goToolchains(packages []pkg.Package) []goToolchain {
+	byVersion := make(map[string]*goToolchain)
+	seen := make(map[string]bool)
+
+	for _, p := range packages {
+		if isGoStdlib(p) {
+			continue
+		}
+		version := goCompiledVersion(p)
+		if version == "" {
+			continue
+		}
+
+		for _, location := range p.Locations.ToSlice() {
+			if seen[location.RealPath] {
+				continue
+			}
+			seen[location.RealPath] = true
+
+			toolchain, ok := byVersion[version]
+			if !ok {
+				toolchain = &goToolchain{version: version}
+				byVersion[version] = toolchain
+			}
+			toolchain.binaries = append(toolchain.binaries, location.RealPath)
+			toolchain.locations = append(toolchain.locations, location)
+		}
+	}
+
+	toolchains := make([]goToolchain, 0, len(byVersion))
+	for _, toolchain := range byVersion {
+		sort.Strings(toolchain.binaries)
+		toolchains = append(toolchains, *toolchain)
+	}
+	sort.Slice(toolchains, func(i, j int) bool {
+		return ParseVersion(goVersion(toolchains[i].version), SchemeSemver).Compare(
+			ParseVersion(goVersion(toolchains[j].version), SchemeSemver)) < 0
+	})
+	return toolchains
+}
+
+// pkg returns the component evaluated for the toolchain; it is matched against the go
+// product like a go binary found by syft's binary cataloger
+func (t goToolchain) pkg() pkg.Package {
+	return pkg.Package{
+		Name:      "go",
+		Version:   t.version,
+		Type:      pkg.BinaryPkg,
+		PURL:      "pkg:golang/stdlib@" + goVersion(t.version),
+		Locations: file.NewLocationSet(t.locations...),
+	}
+}
+
+// goCompiledVersion returns the compiler version recorded in a Go module's build info
+func goCompiledVersion(p pkg.Package) string {
+	switch m := p.Metadata.(type) {
+	case pkg.GolangBinaryBuildinfoEntry:
+		return m.GoCompiledVersion
+	case *pkg.GolangBinaryBuildinfoEntry:
+		return m.GoCompiledVersion
+	default:
+		return ""
+	}
+}
+
+// isGoStdlib reports whether a package is the Go standard library entry syft derives
+// from each Go binary; it duplicates the toolchain component
+func isGoStdlib(p pkg.Package) bool {
+	return p.Type == pkg.GoModulePkg && p.Name == "stdlib"
+}
@@ -0,0 +1,60 @@
+package scanning
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// goModule builds a Go module package found in a binary built with the given compiler
+func goModule(name, binary, compiler string) pkg.Package {
+	return pkg.Package{
+		Name:      name,
+		Type:      pkg.GoModulePkg,
+		Locations: file.NewLocationSet(file.NewLocation(binary)),
+		Metadata:  pkg.GolangBinaryBuildinfoEntry{GoCompiledVersion: compiler},
+	}
+}
+
+// TestGoToolchains tests grouping Go binaries by the compiler version that built them
+func TestGoToolchains(t *testing.T) {
+	packages := []pkg.Package{
+		goModule("github.com/acme/api", "/app/api", "go1.21.5"),
+		goModule("golang.org/x/net", "/app/api", "go1.21.5"),
+		goModule("github.com/acme/worker", "/app/worker", "go1.21.5"),
+		goModule("github.com/acme/cli", "/usr/local/bin/cli", "go1.19.13"),
+		goModule("stdlib", "/usr/local/bin/cli", "go1.19.13"),
+		{Name: "openssl", Type: pkg.DebPkg, Locations: file.NewLocationSet(file.NewLocation("/var/lib/dpkg/status"))},
+	}
+
+	toolchains := goToolchains(packages)
+	if len(toolchains) != 2 {
+		t.Fatalf("goToolchains() returned %d toolchains, want 2", len(toolchains))
+	}
+
+	if toolchains[0].version != "go1.19.13" || !slices.Equal(toolchains[0].binaries, []string{"/usr/local/bin/cli"}) {
+		t.Errorf("toolchain 0 = %+v, want go1.19.13 for /usr/local/bin/cli", toolchains[0])
+	}
+	if toolchains[1].version != "go1.21.5" || !slices.Equal(toolchains[1].binaries, []string{"/app/api", "/app/worker"}) {
+		t.Errorf("toolchain 1 = %+v, want go1.21.5 for /app/api and /app/worker", toolchains[1])
+	}
+
+	if got := goToolchains([]pkg.Package{{Name: "express", Type: pkg.NpmPkg}}); len(got) != 0 {
+		t.Errorf("goToolchains() without Go binaries = %+v, want none", got)
+	}
+}
+
+// TestGoToolchainPackage tests that toolchain components are matched against the go product
+func TestGoToolchainPackage(t *testing.T) {
+	p := goToolchain{version: "go1.21.5", binaries: []string{"/app/api"}}.pkg()
+
+	if p.Name != "go" || p.Version != "go1.21.5" || p.PURL != "pkg:golang/stdlib@1.21.5" {
+		t.Errorf("pkg() = %s %s %s", p.Name, p.Version, p.PURL)
+	}
+	product, version, ok := runtimeProduct(p)
+	if !ok || product != "go" || version != "1.21.5" {
+		t.Errorf("runtimeProduct() = %q, %q, %v, want go 1.21.5", product, version, ok)
+	}
+}
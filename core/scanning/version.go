@@ -0,0 +1,574 @@
+package scanning
+
+import (
+	"strconv"
+	"strings"
+)
+
+// VersionScheme identifies the versioning convention a version string follows
+type VersionScheme string
+
+const (
+	SchemeGeneric VersionScheme = "generic"
+	SchemeDebian  VersionScheme = "debian"
+	SchemeRPM     VersionScheme = "rpm"
+	SchemeAPK     VersionScheme = "apk"
+	SchemeSemver  VersionScheme = "semver"
+	SchemePEP440  VersionScheme = "pep440"
+	SchemeMaven   VersionScheme = "maven"
+	SchemeCalver  VersionScheme = "calver"
+)
+
+// Version is a version string parsed according to its ecosystem's scheme
+type Version struct {
+	Raw       string        // Original version string
+	Scheme    VersionScheme // Scheme the version was parsed with
+	Epoch     int           // Debian/RPM epoch or PEP 440 epoch
+	Upstream  string        // Upstream version without epoch or distro revision
+	Release   []int         // Leading numeric release segments (e.g. [2 4 57])
+	Qualifier string        // Remainder of the upstream version (e.g. "rc1", ".RELEASE", "-alpine")
+	Revision  string        // Packaging revision (Debian revision, RPM release, apk -rN, PEP 440 local)
+}
+
+// versionSchemeForType maps Syft package types to the version scheme of their ecosystem
+func versionSchemeForType(pkgType string) VersionScheme {
+	switch pkgType {
+	case "deb":
+		return SchemeDebian
+	case "rpm":
+		return SchemeRPM
+	case "apk":
+		return SchemeAPK
+	case "python":
+		return SchemePEP440
+	case "java-archive", "jenkins-plugin":
+		return SchemeMaven
+	case "npm", "go-module", "rust-crate", "cargo", "php-composer", "composer", "dotnet", "nuget", "dart-pub", "pub", "hex", "swift":
+		return SchemeSemver
+	default:
+		return SchemeGeneric
+	}
+}
+
+// ParseVersion parses a version string according to the given scheme.
+// Parsing never fails; unparseable versions simply have no Release segments.
+func ParseVersion(raw string, scheme VersionScheme) Version {
+	v := Version{Raw: raw, Scheme: scheme}
+	s := strings.TrimSpace(raw)
+
+	switch scheme {
+	case SchemeDebian, SchemeRPM:
+		// [epoch:]upstream[-revision], the revision starts at the last hyphen
+		s = v.parseEpoch(s, ":")
+		if idx := strings.LastIndex(s, "-"); idx >= 0 {
+			v.Revision = s[idx+1:]
+			s = s[:idx]
+		}
+	case SchemeAPK:
+		// upstream[-rN]
+		if idx := strings.LastIndex(s, "-r"); idx >= 0 && isDigits(s[idx+2:]) {
+			v.Revision = s[idx+1:]
+			s = s[:idx]
+		}
+	case SchemePEP440:
+		// [N!]release[{a|b|rc}N][.postN][.devN][+local]
+		s = trimVersionPrefix(strings.ToLower(s))
+		s = v.parseEpoch(s, "!")
+		if idx := strings.Index(s, "+"); idx >= 0 {
+			v.Revision = s[idx+1:]
+			s = s[:idx]
+		}
+	case SchemeSemver:
+		// Build metadata does not take part in precedence
+		s = trimVersionPrefix(s)
+		if idx := strings.Index(s, "+"); idx >= 0 {
+			s = s[:idx]
+		}
+	case SchemeMaven:
+		// Maven versions carry qualifiers in the version itself (e.g. 5.3.20.RELEASE)
+	default:
+		s = trimVersionPrefix(s)
+		s = v.parseEpoch(s, ":")
+	}
+
+	v.Upstream = s
+	v.Release, v.Qualifier = splitRelease(s)
+
+	if scheme == SchemeGeneric && isCalendarRelease(v.Release) {
+		v.Scheme = SchemeCalver
+	}
+
+	return v
+}
+
+// parseEpoch strips a numeric epoch ending in sep and records it
+func (v *Version) parseEpoch(s, sep string) string {
+	idx := strings.Index(s, sep)
+	if idx <= 0 || !isDigits(s[:idx]) {
+		return s
+	}
+	epoch, err := strconv.Atoi(s[:idx])
+	if err != nil {
+		return s
+	}
+	v.Epoch = epoch
+	return s[idx+1:]
+}
+
+// Major returns the major release number, or an empty string if the version has none
+func (v Version) Major() string {
+	if len(v.Release) == 0 {
+		return ""
+	}
+	return strconv.Itoa(v.Release[0])
+}
+
+// MatchesCycle reports whether the version belongs to a release cycle. Numeric cycles
+// match on release segments (e.g. "3.9" matches 3.9.18 but not 3.91); other cycles
+// (e.g. "bookworm" or "10-lts") are matched textually.
+func (v Version) MatchesCycle(cycle string) bool {
+	if cycle == "" || v.Upstream == "" {
+		return false
+	}
+
+	c := ParseVersion(cycle, SchemeGeneric)
+	if len(c.Release) == 0 || c.Qualifier != "" {
+		return v.Upstream == cycle ||
+			strings.HasPrefix(v.Upstream, cycle+".") ||
+			strings.HasPrefix(v.Upstream, cycle+"-")
+	}
+
+	if len(v.Release) < len(c.Release) {
+		return false
+	}
+	for i := range c.Release {
+		if v.Release[i] != c.Release[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesMajor reports whether the version has the same major release as a cycle
+func (v Version) MatchesMajor(cycle string) bool {
+	major := v.Major()
+	return major != "" && major == ParseVersion(cycle, SchemeGeneric).Major()
+}
+
+// Compare compares two versions of the same scheme, returning -1, 0 or 1
+func (v Version) Compare(other Version) int {
+	if v.Epoch != other.Epoch {
+		return compareInts(v.Epoch, other.Epoch)
+	}
+
+	switch v.Scheme {
+	case SchemeDebian:
+		if c := compareDebianString(v.Upstream, other.Upstream); c != 0 {
+			return c
+		}
+		return compareDebianString(v.Revision, other.Revision)
+	case SchemeRPM:
+		if c := compareRPMString(v.Upstream, other.Upstream); c != 0 {
+			return c
+		}
+		return compareRPMString(v.Revision, other.Revision)
+	}
+
+	if _, c := compareVersionSegments(v.Release, other.Release); c != 0 {
+		return c
+	}
+	if c := compareQualifiers(v.Qualifier, other.Qualifier, v.Scheme); c != 0 {
+		return c
+	}
+	return compareNatural(v.Revision, other.Revision)
+}
+
+// CompareRelease compares only the upstream releases of two versions, ignoring the
+// epoch and packaging revision. Distro package qualifiers (e.g. "~dfsg", "+ds") are
+// packaging suffixes and are ignored too, so a distro package can be compared with
+// a plain upstream version.
+func (v Version) CompareRelease(other Version) int {
+	if _, c := compareVersionSegments(v.Release, other.Release); c != 0 {
+		return c
+	}
+	switch v.Scheme {
+	case SchemeDebian, SchemeRPM, SchemeAPK:
+		return 0
+	}
+	return compareQualifiers(v.Qualifier, other.Qualifier, v.Scheme)
+}
+
+// splitRelease splits the leading dot-separated numeric segments off a version
+func splitRelease(s string) ([]int, string) {
+	var release []int
+	rest := s
+	for {
+		end := 0
+		for end < len(rest) && isDigit(rest[end]) {
+			end++
+		}
+		if end == 0 {
+			break
+		}
+		n, err := strconv.Atoi(rest[:end])
+		if err != nil {
+			break
+		}
+		release = append(release, n)
+		rest = rest[end:]
+		if len(rest) > 1 && rest[0] == '.' && isDigit(rest[1]) {
+			rest = rest[1:]
+			continue
+		}
+		break
+	}
+	return release, rest
+}
+
+// isCalendarRelease reports whether the release starts with a year (e.g. 2024.01.15)
+func isCalendarRelease(release []int) bool {
+	return len(release) > 1 && release[0] >= 1990 && release[0] <= 2100
+}
+
+// compareVersionSegments compares two numeric versions, returning the index of the
+// first differing segment and -1, 0 or 1. Missing segments count as zero.
+func compareVersionSegments(a, b []int) (int, int) {
+	length := len(a)
+	if len(b) > length {
+		length = len(b)
+	}
+	for i := 0; i < length; i++ {
+		x, y := segmentAt(a, i), segmentAt(b, i)
+		if x != y {
+			return i, compareInts(x, y)
+		}
+	}
+	return length, 0
+}
+
+// segmentAt returns the version segment at index i, or zero if it is missing
+func segmentAt(segments []int, i int) int {
+	if i < len(segments) {
+		return segments[i]
+	}
+	return 0
+}
+
+// compareQualifiers orders the non-numeric part of two versions
+func compareQualifiers(a, b string, scheme VersionScheme) int {
+	if a == b {
+		return 0
+	}
+
+	if scheme == SchemeSemver {
+		return compareSemverPrerelease(strings.TrimPrefix(a, "-"), strings.TrimPrefix(b, "-"))
+	}
+
+	rankA, rankB := qualifierRank(a), qualifierRank(b)
+	if rankA != rankB {
+		return compareInts(rankA, rankB)
+	}
+	if rankA == 0 {
+		// Final release markers (RELEASE, Final, GA) are equivalent to no qualifier
+		return 0
+	}
+	return compareNatural(strings.ToLower(strings.TrimLeft(a, ".-_~+")), strings.ToLower(strings.TrimLeft(b, ".-_~+")))
+}
+
+// qualifierRank orders common pre- and post-release qualifiers across ecosystems
+// (PEP 440, Maven, generic): dev < alpha < beta < milestone < rc < snapshot < release < post
+func qualifierRank(q string) int {
+	q = strings.ToLower(strings.TrimLeft(q, ".-_~+"))
+	if q == "" {
+		return 0
+	}
+
+	end := 0
+	for end < len(q) && q[end] >= 'a' && q[end] <= 'z' {
+		end++
+	}
+
+	switch q[:end] {
+	case "dev":
+		return -6
+	case "a", "alpha":
+		return -5
+	case "b", "beta":
+		return -4
+	case "m", "milestone":
+		return -3
+	case "c", "rc", "cr", "pre", "preview":
+		return -2
+	case "snapshot":
+		return -1
+	case "final", "ga", "release":
+		return 0
+	default:
+		return 1
+	}
+}
+
+// compareSemverPrerelease compares semver pre-release identifiers; a version
+// without a pre-release has higher precedence than one with
+func compareSemverPrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		x, y := aParts[i], bParts[i]
+		xNum, yNum := isDigits(x), isDigits(y)
+		switch {
+		case xNum && yNum:
+			xi, _ := strconv.Atoi(x)
+			yi, _ := strconv.Atoi(y)
+			if xi != yi {
+				return compareInts(xi, yi)
+			}
+		case xNum:
+			return -1
+		case yNum:
+			return 1
+		default:
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInts(len(aParts), len(bParts))
+}
+
+// compareDebianString compares an upstream version or revision following dpkg's
+// algorithm: non-digit runs compare with '~' before everything (even the end of the
+// string) and letters before other characters, digit runs compare numerically
+func compareDebianString(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ac, bc := debianOrder(a, i), debianOrder(b, j)
+			if ac != bc {
+				return compareInts(ac, bc)
+			}
+			i++
+			j++
+		}
+
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+
+		firstDiff := 0
+		for i < len(a) && j < len(b) && isDigit(a[i]) && isDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = compareInts(int(a[i]), int(b[j]))
+			}
+			i++
+			j++
+		}
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return firstDiff
+		}
+	}
+	return 0
+}
+
+// debianOrder returns the dpkg sort weight of the character at index i
+func debianOrder(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+	c := s[i]
+	switch {
+	case isDigit(c):
+		return 0
+	case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		return int(c)
+	case c == '~':
+		return -1
+	default:
+		return int(c) + 256
+	}
+}
+
+// compareRPMString compares a version or release following rpmvercmp: alphanumeric
+// segments compare numerically or lexically, numeric segments are newer than alphabetic
+// ones, '~' sorts before everything and '^' after the base version
+func compareRPMString(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	for {
+		a = strings.TrimLeftFunc(a, isRPMSeparator)
+		b = strings.TrimLeftFunc(b, isRPMSeparator)
+
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			switch {
+			case a == "":
+				return -1
+			case b == "":
+				return 1
+			case !strings.HasPrefix(a, "^"):
+				return 1
+			case !strings.HasPrefix(b, "^"):
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if a == "" || b == "" {
+			break
+		}
+
+		numeric := isDigit(a[0])
+		segA, restA := splitRPMSegment(a, numeric)
+		segB, restB := splitRPMSegment(b, numeric)
+		if segB == "" {
+			// Numeric segments are newer than alphabetic ones
+			if numeric {
+				return 1
+			}
+			return -1
+		}
+
+		if numeric {
+			segA = strings.TrimLeft(segA, "0")
+			segB = strings.TrimLeft(segB, "0")
+			if len(segA) != len(segB) {
+				return compareInts(len(segA), len(segB))
+			}
+		}
+		if c := strings.Compare(segA, segB); c != 0 {
+			return c
+		}
+		a, b = restA, restB
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	default:
+		return 1
+	}
+}
+
+// splitRPMSegment splits a leading numeric or alphabetic run off s
+func splitRPMSegment(s string, numeric bool) (string, string) {
+	end := 0
+	for end < len(s) {
+		c := s[end]
+		if numeric && !isDigit(c) {
+			break
+		}
+		if !numeric && !isLetter(c) {
+			break
+		}
+		end++
+	}
+	return s[:end], s[end:]
+}
+
+// isRPMSeparator reports whether r is ignored between rpm version segments
+func isRPMSeparator(r rune) bool {
+	return r < 128 && !isDigit(byte(r)) && !isLetter(byte(r)) && r != '~' && r != '^'
+}
+
+// compareNatural compares strings treating digit runs as numbers (e.g. r2 < r10)
+func compareNatural(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			si, sj := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+			x := strings.TrimLeft(a[si:i], "0")
+			y := strings.TrimLeft(b[sj:j], "0")
+			if len(x) != len(y) {
+				return compareInts(len(x), len(y))
+			}
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+			continue
+		}
+		if a[i] != b[j] {
+			return compareInts(int(a[i]), int(b[j]))
+		}
+		i++
+		j++
+	}
+	return compareInts(len(a)-i, len(b)-j)
+}
+
+// trimVersionPrefix removes a leading "v" from versions such as v1.21.5
+func trimVersionPrefix(s string) string {
+	if len(s) > 1 && (s[0] == 'v' || s[0] == 'V') && isDigit(s[1]) {
+		return s[1:]
+	}
+	return s
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,428 @@
+package scanning
+
+import (
+	"testing"
+)
+
+// TestVersionMatchesCycle tests cycle matching across version schemes
+func TestVersionMatchesCycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		scheme  VersionScheme
+		cycle   string
+		want    bool
+	}{
+		{
+			name:    "exact match",
+			version: "3.9",
+			cycle:   "3.9",
+			want:    true,
+		},
+		{
+			name:    "version with patch matches cycle",
+			version: "3.9.1",
+			cycle:   "3.9",
+			want:    true,
+		},
+		{
+			name:    "version with dash suffix matches cycle",
+			version: "3.9-alpine",
+			cycle:   "3.9",
+			want:    true,
+		},
+		{
+			name:    "version with multiple patches matches cycle",
+			version: "3.9.10.2",
+			cycle:   "3.9",
+			want:    true,
+		},
+		{
+			name:    "version does not match different cycle",
+			version: "3.10.1",
+			cycle:   "3.9",
+			want:    false,
+		},
+		{
+			name:    "partial match should fail",
+			version: "3.91",
+			cycle:   "3.9",
+			want:    false,
+		},
+		{
+			name:    "major version only",
+			version: "22",
+			cycle:   "22",
+			want:    true,
+		},
+		{
+			name:    "version shorter than cycle",
+			version: "3",
+			cycle:   "3.9",
+			want:    false,
+		},
+		{
+			name:    "empty version",
+			version: "",
+			cycle:   "3.9",
+			want:    false,
+		},
+		{
+			name:    "empty cycle",
+			version: "3.9.1",
+			cycle:   "",
+			want:    false,
+		},
+		{
+			name:    "debian epoch and revision",
+			version: "1:2.4.57-2+deb12u1",
+			scheme:  SchemeDebian,
+			cycle:   "2.4",
+			want:    true,
+		},
+		{
+			name:    "debian tilde revision",
+			version: "3.0.11-1~deb12u2",
+			scheme:  SchemeDebian,
+			cycle:   "3.0",
+			want:    true,
+		},
+		{
+			name:    "apk revision",
+			version: "3.11.4-r0",
+			scheme:  SchemeAPK,
+			cycle:   "3.11",
+			want:    true,
+		},
+		{
+			name:    "rpm epoch and release",
+			version: "1:2.4.37-62.module+el8.9.0+19699+7a7a2044",
+			scheme:  SchemeRPM,
+			cycle:   "2.4",
+			want:    true,
+		},
+		{
+			name:    "pep440 release candidate",
+			version: "3.12.0rc1",
+			scheme:  SchemePEP440,
+			cycle:   "3.12",
+			want:    true,
+		},
+		{
+			name:    "maven qualifier",
+			version: "5.3.20.RELEASE",
+			scheme:  SchemeMaven,
+			cycle:   "5.3",
+			want:    true,
+		},
+		{
+			name:    "semver with v prefix",
+			version: "v1.21.5",
+			scheme:  SchemeSemver,
+			cycle:   "1.21",
+			want:    true,
+		},
+		{
+			name:    "calendar version with zero-padded month",
+			version: "22.04",
+			cycle:   "22.04",
+			want:    true,
+		},
+		{
+			name:    "non-numeric cycle matched textually",
+			version: "10-lts.1",
+			cycle:   "10-lts",
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := tt.scheme
+			if scheme == "" {
+				scheme = SchemeGeneric
+			}
+			got := ParseVersion(tt.version, scheme).MatchesCycle(tt.cycle)
+			if got != tt.want {
+				t.Errorf("ParseVersion(%q, %s).MatchesCycle(%q) = %v, want %v", tt.version, scheme, tt.cycle, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestVersionMatchesMajor tests major version matching
+func TestVersionMatchesMajor(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		cycle   string
+		want    bool
+	}{
+		{
+			name:    "same major version",
+			version: "3.9.1",
+			cycle:   "3.8",
+			want:    true,
+		},
+		{
+			name:    "different major versions",
+			version: "4.0.0",
+			cycle:   "3.9",
+			want:    false,
+		},
+		{
+			name:    "version with v prefix",
+			version: "v2.1.0",
+			cycle:   "2.0",
+			want:    true,
+		},
+		{
+			name:    "single digit versions",
+			version: "22",
+			cycle:   "22",
+			want:    true,
+		},
+		{
+			name:    "version with dash suffix",
+			version: "3-alpine",
+			cycle:   "3",
+			want:    true,
+		},
+		{
+			name:    "empty version",
+			version: "",
+			cycle:   "3",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseVersion(tt.version, SchemeGeneric).MatchesMajor(tt.cycle)
+			if got != tt.want {
+				t.Errorf("ParseVersion(%q).MatchesMajor(%q) = %v, want %v", tt.version, tt.cycle, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestVersionMajor tests major version extraction
+func TestVersionMajor(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{
+			name:    "semver format",
+			version: "3.9.1",
+			want:    "3",
+		},
+		{
+			name:    "with v prefix",
+			version: "v2.1.0",
+			want:    "2",
+		},
+		{
+			name:    "single number",
+			version: "22",
+			want:    "22",
+		},
+		{
+			name:    "dash separator",
+			version: "3-alpine",
+			want:    "3",
+		},
+		{
+			name:    "underscore separator",
+			version: "3_0_1",
+			want:    "3",
+		},
+		{
+			name:    "empty string",
+			version: "",
+			want:    "",
+		},
+		{
+			name:    "not a version",
+			version: "latest",
+			want:    "",
+		},
+		{
+			name:    "complex version",
+			version: "2024.01.15",
+			want:    "2024",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseVersion(tt.version, SchemeGeneric).Major()
+			if got != tt.want {
+				t.Errorf("ParseVersion(%q).Major() = %q, want %q", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseVersion tests the parsed components of versions in each scheme
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version      string
+		scheme       VersionScheme
+		wantScheme   VersionScheme
+		wantEpoch    int
+		wantUpstream string
+		wantRelease  []int
+		wantRevision string
+	}{
+		{"1:2.4.57-2+deb12u1", SchemeDebian, SchemeDebian, 1, "2.4.57", []int{2, 4, 57}, "2+deb12u1"},
+		{"2.36-9+deb12u4", SchemeDebian, SchemeDebian, 0, "2.36", []int{2, 36}, "9+deb12u4"},
+		{"1.2.13.dfsg-1", SchemeDebian, SchemeDebian, 0, "1.2.13.dfsg", []int{1, 2, 13}, "1"},
+		{"3.11.4-r0", SchemeAPK, SchemeAPK, 0, "3.11.4", []int{3, 11, 4}, "r0"},
+		{"1.36.1-r15", SchemeAPK, SchemeAPK, 0, "1.36.1", []int{1, 36, 1}, "r15"},
+		{"2:8.2.2637-20.el9_1", SchemeRPM, SchemeRPM, 2, "8.2.2637", []int{8, 2, 2637}, "20.el9_1"},
+		{"1!2.0.0rc1+local.1", SchemePEP440, SchemePEP440, 1, "2.0.0rc1", []int{2, 0, 0}, "local.1"},
+		{"v1.2.3-beta.1+build.5", SchemeSemver, SchemeSemver, 0, "1.2.3-beta.1", []int{1, 2, 3}, ""},
+		{"2.7.0-SNAPSHOT", SchemeMaven, SchemeMaven, 0, "2.7.0-SNAPSHOT", []int{2, 7, 0}, ""},
+		{"2024.01.15", SchemeGeneric, SchemeCalver, 0, "2024.01.15", []int{2024, 1, 15}, ""},
+		{"1:20.1.0", SchemeGeneric, SchemeGeneric, 1, "20.1.0", []int{20, 1, 0}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			v := ParseVersion(tt.version, tt.scheme)
+			if v.Scheme != tt.wantScheme {
+				t.Errorf("Scheme = %s, want %s", v.Scheme, tt.wantScheme)
+			}
+			if v.Epoch != tt.wantEpoch {
+				t.Errorf("Epoch = %d, want %d", v.Epoch, tt.wantEpoch)
+			}
+			if v.Upstream != tt.wantUpstream {
+				t.Errorf("Upstream = %q, want %q", v.Upstream, tt.wantUpstream)
+			}
+			if v.Revision != tt.wantRevision {
+				t.Errorf("Revision = %q, want %q", v.Revision, tt.wantRevision)
+			}
+			if len(v.Release) != len(tt.wantRelease) {
+				t.Fatalf("Release = %v, want %v", v.Release, tt.wantRelease)
+			}
+			for i := range v.Release {
+				if v.Release[i] != tt.wantRelease[i] {
+					t.Errorf("Release = %v, want %v", v.Release, tt.wantRelease)
+				}
+			}
+		})
+	}
+}
+
+// TestVersionCompare tests version ordering in each scheme
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		scheme VersionScheme
+		a, b   string
+		want   int
+	}{
+		// Debian
+		{SchemeDebian, "2.4.57-2", "2.4.57-2", 0},
+		{SchemeDebian, "1:1.0-1", "2.0-1", 1},
+		{SchemeDebian, "1.0~rc1-1", "1.0-1", -1},
+		{SchemeDebian, "2.36-9+deb12u3", "2.36-9+deb12u4", -1},
+		{SchemeDebian, "3.0.11-1~deb12u2", "3.0.11-1", -1},
+		{SchemeDebian, "1.2.10-1", "1.2.9-1", 1},
+		{SchemeDebian, "1.0a-1", "1.0-1", 1},
+		// RPM
+		{SchemeRPM, "8.2.2637-20.el9_1", "8.2.2637-20.el9_1", 0},
+		{SchemeRPM, "1.0-1.el8", "1.0-2.el8", -1},
+		{SchemeRPM, "1.0~rc1-1", "1.0-1", -1},
+		{SchemeRPM, "1.0^git1-1", "1.0-1", 1},
+		{SchemeRPM, "1.10-1", "1.9-1", 1},
+		{SchemeRPM, "1.0a-1", "1.0.1-1", -1},
+		// APK
+		{SchemeAPK, "3.11.4-r0", "3.11.4-r1", -1},
+		{SchemeAPK, "3.11.4-r10", "3.11.4-r9", 1},
+		{SchemeAPK, "3.11.5-r0", "3.11.4-r9", 1},
+		// Semver
+		{SchemeSemver, "1.0.0-alpha", "1.0.0", -1},
+		{SchemeSemver, "1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{SchemeSemver, "1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{SchemeSemver, "1.0.0+build.1", "1.0.0+build.2", 0},
+		{SchemeSemver, "v2.0.0", "1.9.9", 1},
+		// PEP 440
+		{SchemePEP440, "1.0.dev1", "1.0a1", -1},
+		{SchemePEP440, "1.0a1", "1.0b1", -1},
+		{SchemePEP440, "1.0rc1", "1.0", -1},
+		{SchemePEP440, "1.0.post1", "1.0", 1},
+		{SchemePEP440, "1!1.0", "2.0", 1},
+		// Maven
+		{SchemeMaven, "5.3.20.RELEASE", "5.3.20", 0},
+		{SchemeMaven, "2.7.0-SNAPSHOT", "2.7.0", -1},
+		{SchemeMaven, "2.7.0-M1", "2.7.0-RC1", -1},
+		{SchemeMaven, "6.4.4.Final", "6.4.10.Final", -1},
+		// Calendar versions
+		{SchemeGeneric, "2024.01.15", "2024.1.15", 0},
+		{SchemeGeneric, "2023.12", "2024.01", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.scheme)+"/"+tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			a := ParseVersion(tt.a, tt.scheme)
+			b := ParseVersion(tt.b, tt.scheme)
+			if got := a.Compare(b); got != tt.want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := b.Compare(a); got != -tt.want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+			}
+		})
+	}
+}
+
+// TestVersionCompareRelease tests comparing distro package versions with plain upstream releases
+func TestVersionCompareRelease(t *testing.T) {
+	tests := []struct {
+		scheme VersionScheme
+		a, b   string
+		want   int
+	}{
+		{SchemeDebian, "1:2.4.57-2+deb12u1", "2.4.62", -1},
+		{SchemeDebian, "1:2.4.62-1", "2.4.62", 0},
+		{SchemeDebian, "2.4.62~dfsg-1", "2.4.62", 0},
+		{SchemeRPM, "3.11.7-1.el9", "3.11.7", 0},
+		{SchemeRPM, "3.11.5-1.el9", "3.11.7", -1},
+		{SchemeAPK, "3.11.8-r0", "3.11.7", 1},
+		{SchemeSemver, "1.0.0-rc.1", "1.0.0", -1},
+		{SchemePEP440, "1!1.0.post1", "1.0", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.scheme)+"/"+tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			a := ParseVersion(tt.a, tt.scheme)
+			b := ParseVersion(tt.b, tt.scheme)
+			if got := a.CompareRelease(b); got != tt.want {
+				t.Errorf("CompareRelease(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestVersionSchemeForType tests the mapping of Syft package types to version schemes
+func TestVersionSchemeForType(t *testing.T) {
+	tests := map[string]VersionScheme{
+		"deb":          SchemeDebian,
+		"rpm":          SchemeRPM,
+		"apk":          SchemeAPK,
+		"python":       SchemePEP440,
+		"java-archive": SchemeMaven,
+		"npm":          SchemeSemver,
+		"go-module":    SchemeSemver,
+		"os":           SchemeGeneric,
+		"":             SchemeGeneric,
+	}
+
+	for pkgType, want := range tests {
+		if got := versionSchemeForType(pkgType); got != want {
+			t.Errorf("versionSchemeForType(%q) = %s, want %s", pkgType, got, want)
+		}
+	}
+}